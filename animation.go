@@ -0,0 +1,425 @@
+package main
+
+import (
+	"context"
+	"image"
+	"runtime"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// artworkAnimation is an artwork animation mode (artwork.animation). Frames
+// are evenly spaced over one loop of the animation and rendered ahead of time
+// by renderAnimationFrames, so implementations only describe what a frame
+// looks like and how long a loop takes.
+type artworkAnimation interface {
+	// prepare turns resized artwork into the base image frames are made from.
+	// Called once per track, off the UI goroutine.
+	prepare(img image.Image, cfg Config) *image.RGBA
+	// frame renders frame index of total from the prepared image. Frame 0 is
+	// also the still shown while the other frames render. Must not modify
+	// prepared: frames render concurrently.
+	frame(prepared *image.RGBA, index int, total int) image.Image
+	// maxFrames is how many frames one loop is rendered with at most (the
+	// memory budget may lower it)
+	maxFrames(cfg Config) int
+	// cycle is how long one loop takes - the tick policy frame timing is
+	// derived from. 0 means the animation doesn't move.
+	cycle(cfg Config) time.Duration
+}
+
+// animationNone is the artwork.animation value for static artwork
+const animationNone = "none"
+
+// animations maps artwork.animation values to their implementation
+var animations = map[string]artworkAnimation{
+	"vinyl":    vinylAnimation{},
+	"cassette": cassetteAnimation{},
+	"cd":       cdAnimation{},
+	"kenburns": kenBurnsAnimation{},
+	"pulse":    pulseAnimation{},
+}
+
+// animationOrder is the order the 'v' key cycles through
+var animationOrder = []string{animationNone, "vinyl", "cassette", "cd", "kenburns", "pulse"}
+
+// animationName resolves the animation mode to use. vinyl_mode predates
+// artwork.animation and still turns on vinyl when no animation is chosen.
+func animationName(cfg Config) string {
+	if cfg.Artwork.Animation != animationNone && cfg.Artwork.Animation != "" {
+		return cfg.Artwork.Animation
+	}
+	if cfg.Artwork.VinylMode {
+		return "vinyl"
+	}
+	return animationNone
+}
+
+// activeAnimation returns the configured animation, or nil for static artwork
+func activeAnimation(cfg Config) artworkAnimation {
+	return animations[animationName(cfg)]
+}
+
+// nextAnimationName returns the animation after name in animationOrder,
+// wrapping around to static artwork
+func nextAnimationName(name string) string {
+	for i, n := range animationOrder {
+		if n == name {
+			return animationOrder[(i+1)%len(animationOrder)]
+		}
+	}
+	return animationOrder[0]
+}
+
+const (
+	// animMinCoarseFrames is how many evenly spaced frames must exist before
+	// the animation starts playing at a coarse step (8 frames = 45° per step
+	// for the vinyl record)
+	animMinCoarseFrames = 8
+
+	// animMinFrames is the fewest frames the memory budget may cut us down to
+	animMinFrames = 2
+)
+
+// One rendered animation frame, delivered progressively while the rest render
+type animationFrameMsg struct {
+	job     int           // Generation job this frame belongs to (stale jobs are ignored)
+	trackID string        // Track ID this frame belongs to
+	index   int           // Frame index (position in the loop = index/total)
+	total   int           // Total frames in this job (after the memory budget)
+	frame   string        // Kitty transmit command for this frame
+	cycle   time.Duration // Loop duration (an animated cover's comes from its frame delays)
+	ch      chan tea.Msg  // Channel the next frame arrives on
+}
+
+// animationFrameBudget returns how many frames fit in the memory budget given the
+// size of one encoded frame, capped at maxFrames
+func animationFrameBudget(frameBytes int, maxFrames int, memoryMB int) int {
+	if frameBytes <= 0 {
+		return maxFrames
+	}
+	frames := memoryMB * 1024 * 1024 / frameBytes
+	if frames > maxFrames {
+		frames = maxFrames
+	}
+	if frames < animMinFrames {
+		frames = animMinFrames
+	}
+	return frames
+}
+
+// frameStepChain returns the coarse-to-fine frame steps for a frame count,
+// each dividing the previous one, ending at 1. Frames are rendered step by
+// step so that every multiple of a step exists before finer ones start,
+// e.g. 90 frames → [10 5 1]: 9 frames, then 18, then all 90.
+func frameStepChain(total int) []int {
+	step := 1
+	for d := total; d > 1; d-- {
+		if total%d == 0 && total/d >= animMinCoarseFrames {
+			step = d
+			break
+		}
+	}
+
+	chain := []int{step}
+	for step > 1 {
+		next := 1
+		for d := step - 1; d > 1; d-- {
+			if step%d == 0 {
+				next = d
+				break
+			}
+		}
+		step = next
+		chain = append(chain, step)
+	}
+	return chain
+}
+
+// frameRenderOrder lists frame indices coarse steps first (see frameStepChain)
+func frameRenderOrder(total int) []int {
+	seen := make([]bool, total)
+	order := make([]int, 0, total)
+	for _, step := range frameStepChain(total) {
+		for i := 0; i < total; i += step {
+			if !seen[i] {
+				seen[i] = true
+				order = append(order, i)
+			}
+		}
+	}
+	return order
+}
+
+// frameCoarseStep returns the finest step at which every frame is present,
+// or 0 while not even the coarsest step is complete
+func frameCoarseStep(frames []string) int {
+	best := 0
+	for _, step := range frameStepChain(len(frames)) {
+		for i := 0; i < len(frames); i += step {
+			if frames[i] == "" {
+				return best
+			}
+		}
+		best = step
+	}
+	return best
+}
+
+// startAnimationFrames cancels any in-flight frame generation and starts a new
+// job for the current artwork. Frames render on a worker pool and arrive one
+// animationFrameMsg at a time (or as a single animationUploadMsg when the terminal
+// animates natively).
+func (m *model) startAnimationFrames(cfg Config) tea.Cmd {
+	anim := activeAnimation(cfg)
+	cover := m.coverActive(cfg)
+	if anim == nil && !cover {
+		return nil
+	}
+	if m.animCancel != nil {
+		m.animCancel()
+	}
+	// The previous job's file-mode frames are superseded (frames regenerated
+	// after a config change would otherwise stay until the next track)
+	kittyFiles.release(kittyOwnerAnimation(m.animJob))
+	ctx, cancel := context.WithCancel(context.Background())
+	m.animCancel = cancel
+	m.animJob++
+	m.animCacheTrackID = m.lastTrackID
+	m.animCachedName = m.animationKey(cfg)
+	m.animCachedBudget = cfg.Artwork.VinylMemoryMB
+	buffer := coverMaxSlots
+	if anim != nil {
+		m.animCachedFrames = anim.maxFrames(cfg)
+		buffer = anim.maxFrames(cfg)
+	}
+
+	job := m.animJob
+	trackID := m.lastTrackID
+	rawArtwork := m.rawArtworkData
+	native := m.useNativeAnimation(cfg)
+	ch := make(chan tea.Msg, buffer+1)
+
+	return func() tea.Msg {
+		go renderAnimationFrames(ctx, ch, job, trackID, rawArtwork, cfg, native)
+		return waitAnimationFrame(ch)
+	}
+}
+
+// waitAnimationFrameCmd waits for the next result of a frame generation job
+func waitAnimationFrameCmd(ch chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return waitAnimationFrame(ch)
+	}
+}
+
+func waitAnimationFrame(ch chan tea.Msg) tea.Msg {
+	msg, ok := <-ch
+	if !ok {
+		// Job finished or was cancelled
+		return nil
+	}
+	return msg
+}
+
+// renderAnimationFrames renders one job's frames and sends them on ch, closing it
+// when done. Checks ctx between frames so a track change stops the work.
+func renderAnimationFrames(ctx context.Context, ch chan tea.Msg, job int, trackID string, rawArtwork []byte, cfg Config, native bool) {
+	defer close(ch)
+	// Malformed images can panic inside image decoders; never crash the UI
+	defer func() {
+		_ = recover()
+	}()
+	owner := kittyOwnerAnimation(job)
+	defer func() {
+		// A cancelled job's frames may have been written after the model
+		// released them, and nothing will send them now
+		if ctx.Err() != nil {
+			kittyFiles.release(owner)
+		}
+	}()
+
+	anim := activeAnimation(cfg)
+	if anim == nil {
+		// No animation configured: play the cover's own frames
+		cover, err := newCoverAnimation(rawArtwork, cfg)
+		if err != nil {
+			return
+		}
+		anim = cover
+	}
+
+	img, err := decodeArtworkData(rawArtwork)
+	if err != nil {
+		return
+	}
+	prepared := prepareArtwork(img, hashBytes(rawArtwork), cfg)
+
+	// Frame 0 is the same whatever the final count, so render it first and
+	// use its size to decide how many frames the memory budget allows
+	first, err := encodeFramePNG(anim, prepared, 0, 1)
+	if err != nil {
+		return
+	}
+	// Inline transmission inflates PNG data by 4/3 (base64)
+	total := animationFrameBudget(len(first)*4/3, anim.maxFrames(cfg), cfg.Artwork.VinylMemoryMB)
+
+	pngFrames := make([][]byte, total)
+	pngFrames[0] = first
+	cycle := anim.cycle(cfg)
+
+	send := func(msg tea.Msg) bool {
+		select {
+		case ch <- msg:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	if !native {
+		encoded, err := kittyTransmit(first, kittyImageID, cfg.Artwork.Transmission, owner)
+		if err != nil || !send(animationFrameMsg{job: job, trackID: trackID, index: 0, total: total, frame: encoded, cycle: cycle, ch: ch}) {
+			return
+		}
+	}
+
+	// Fan the remaining frames out to a worker pool, coarse steps first
+	indices := make(chan int)
+	results := make(chan struct{})
+	workers := runtime.NumCPU()
+	if workers > total-1 {
+		workers = total - 1
+	}
+	// deliver sends frame i, false once the job is cancelled
+	deliver := func(i int, data []byte) bool {
+		encoded, err := kittyTransmit(data, kittyImageID, cfg.Artwork.Transmission, owner)
+		if err != nil {
+			return true
+		}
+		return send(animationFrameMsg{job: job, trackID: trackID, index: i, total: total, frame: encoded, cycle: cycle, ch: ch})
+	}
+	// A frame that fails twice is filled in once the rest are done: a gap
+	// would keep playback from ever starting
+	var failedMu sync.Mutex
+	failed := make([]bool, total)
+	renderFrame := func(i int) bool {
+		data := renderFramePNG(anim, prepared, i, total)
+		if data == nil {
+			failedMu.Lock()
+			failed[i] = true
+			failedMu.Unlock()
+			return true
+		}
+		if native {
+			// Distinct indices, so no lock needed
+			pngFrames[i] = data
+			return true
+		}
+		return deliver(i, data)
+	}
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer func() { results <- struct{}{} }()
+			for i := range indices {
+				if !renderFrame(i) {
+					return
+				}
+			}
+		}()
+	}
+
+	for _, i := range frameRenderOrder(total)[1:] {
+		select {
+		case indices <- i:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(indices)
+	for w := 0; w < workers; w++ {
+		<-results
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	// Fill each failed frame with its nearest rendered neighbour (frame 0
+	// always rendered)
+	for i := range failed {
+		if !failed[i] {
+			continue
+		}
+		src := nearestRenderedFrame(failed, i)
+		if native {
+			pngFrames[i] = pngFrames[src]
+			continue
+		}
+		data := renderFramePNG(anim, prepared, src, total)
+		if data == nil {
+			data = first
+		}
+		if !deliver(i, data) {
+			return
+		}
+	}
+
+	if !native {
+		return
+	}
+	upload, err := kittyAnimationUpload(pngFrames, kittyImageID, frameGapMs(cycle, total), cfg.Artwork.Transmission, owner)
+	if err == nil {
+		send(animationUploadMsg{job: job, upload: upload, trackID: trackID, cycle: cycle})
+		return
+	}
+	// No terminal-side animation, so play the frames from the cache instead
+	for i, data := range pngFrames {
+		if !deliver(i, data) {
+			return
+		}
+	}
+}
+
+// renderFramePNG is encodeFramePNG for a frame job: it tries twice, and
+// returns nil if both fail. The job's deferred recover only covers its own
+// goroutine, so each attempt recovers on its own.
+func renderFramePNG(anim artworkAnimation, prepared image.Image, index int, total int) []byte {
+	for attempt := 0; attempt < 2; attempt++ {
+		if data := tryFramePNG(anim, prepared, index, total); data != nil {
+			return data
+		}
+	}
+	return nil
+}
+
+// tryFramePNG is one attempt at encodeFramePNG, nil on error or panic
+func tryFramePNG(anim artworkAnimation, prepared image.Image, index int, total int) (data []byte) {
+	defer func() {
+		if recover() != nil {
+			data = nil
+		}
+	}()
+	data, err := encodeFramePNG(anim, prepared, index, total)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// nearestRenderedFrame returns the frame closest to i (the animation loops)
+// that didn't fail
+func nearestRenderedFrame(failed []bool, i int) int {
+	total := len(failed)
+	for d := 1; d < total; d++ {
+		for _, j := range []int{(i + d) % total, (i - d + total) % total} {
+			if !failed[j] {
+				return j
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"image"
+	"math"
+	"time"
+)
+
+const (
+	// Loop durations and frame counts for the non-vinyl animations
+	cassetteCycle  = 2 * time.Second // One turn of the reels
+	cassetteFrames = 24
+	cdCycle        = 4 * time.Second // One sweep of the sheen around the disc
+	cdFrames       = 36
+	kenBurnsCycle  = 20 * time.Second // Zoom in and back out
+	kenBurnsFrames = 60
+	pulseCycle     = 3 * time.Second // One breath
+	pulseFrames    = 24
+
+	// Cassette proportions, relative to the shell's width (x) or height (y)
+	cassetteAspect    = 0.64 // Shell height / width
+	cassetteReelX     = 0.14 // Reel centers sit this far either side of the middle
+	cassetteReelY     = 0.42
+	cassetteHubRadius = 0.09 // Relative to height
+
+	// CD proportions, relative to the disc's radius
+	cdHoleRadius = 0.12 // Center hole
+	cdHubRadius  = 0.30 // Clear plastic hub around the hole
+	cdSheen      = 0.45 // Strength of the iridescent streaks (0-1)
+
+	kenBurnsZoom = 0.15 // Extra zoom at the closest point of the loop
+	pulseScale   = 0.03 // How much the artwork shrinks at the low point of a pulse
+	pulseDim     = 0.15 // How much it darkens at the low point of a pulse
+)
+
+// Colors shared by the cassette shell and reels
+var (
+	cassetteShell  = [3]uint8{40, 40, 46}
+	cassetteWindow = [3]uint8{20, 20, 24}
+	cassetteBase   = [3]uint8{58, 58, 66}
+	cassetteHub    = [3]uint8{232, 232, 226}
+)
+
+// loopWave maps frame index of total to 0 → 1 → 0 over one loop, easing in
+// and out so the loop has no visible seam
+func loopWave(index int, total int) float64 {
+	return (1 - math.Cos(2*math.Pi*float64(index)/float64(total))) / 2
+}
+
+// cloneRGBA returns a copy of img that frames can draw on
+func cloneRGBA(img *image.RGBA) *image.RGBA {
+	clone := image.NewRGBA(img.Rect)
+	copy(clone.Pix, img.Pix)
+	return clone
+}
+
+// roundedRectCoverage returns how much of the pixel centered at (px, py) lies
+// inside the rectangle (x0, y0)-(x1, y1) with corners rounded by radius
+func roundedRectCoverage(px, py, x0, y0, x1, y1, radius float64) float64 {
+	halfW, halfH := (x1-x0)/2, (y1-y0)/2
+	qx := math.Abs(px-(x0+halfW)) - (halfW - radius)
+	qy := math.Abs(py-(y0+halfH)) - (halfH - radius)
+	// Signed distance to the rounded rectangle's edge (negative inside)
+	dist := math.Hypot(math.Max(qx, 0), math.Max(qy, 0)) + math.Min(math.Max(qx, qy), 0) - radius
+	return clamp01(0.5 - dist)
+}
+
+// fillRoundedRect composites a solid rounded rectangle onto img
+func fillRoundedRect(img *image.RGBA, x0, y0, x1, y1, radius float64, rgb [3]uint8) {
+	for y := int(y0); y < int(math.Ceil(y1)) && y < img.Rect.Dy(); y++ {
+		for x := int(x0); x < int(math.Ceil(x1)) && x < img.Rect.Dx(); x++ {
+			if c := roundedRectCoverage(float64(x)+0.5, float64(y)+0.5, x0, y0, x1, y1, radius); c > 0 {
+				blendPixel(img, x, y, rgb, c)
+			}
+		}
+	}
+}
+
+// fillCircle composites a solid anti-aliased circle onto img
+func fillCircle(img *image.RGBA, cx, cy, radius float64, rgb [3]uint8) {
+	for y := int(cy - radius); y <= int(cy+radius) && y < img.Rect.Dy(); y++ {
+		for x := int(cx - radius); x <= int(cx+radius) && x < img.Rect.Dx(); x++ {
+			if x < 0 || y < 0 {
+				continue
+			}
+			dist := math.Hypot(float64(x)+0.5-cx, float64(y)+0.5-cy)
+			if c := clamp01(radius - dist + 0.5); c > 0 {
+				blendPixel(img, x, y, rgb, c)
+			}
+		}
+	}
+}
+
+// cassetteAnimation puts the artwork on a cassette label with spinning reels
+// (artwork.animation: cassette)
+type cassetteAnimation struct{}
+
+// prepare draws the cassette shell with the artwork as its label. The reels
+// are left out: frame draws them at each angle.
+func (cassetteAnimation) prepare(img image.Image, cfg Config) *image.RGBA {
+	src := toRGBA(img)
+	w := float64(src.Rect.Dx())
+	h := math.Round(w * cassetteAspect)
+	shell := image.NewRGBA(image.Rect(0, 0, int(w), int(h)))
+
+	fillRoundedRect(shell, 0, 0, w, h, w*0.05, cassetteShell)
+
+	// Label: the artwork scaled to cover the area, cropped around its center
+	lx0, ly0, lx1, ly1 := w*0.06, h*0.07, w*0.94, h*0.66
+	scale := math.Max((lx1-lx0)/w, (ly1-ly0)/float64(src.Rect.Dy()))
+	offX := (w - (lx1-lx0)/scale) / 2
+	offY := (float64(src.Rect.Dy()) - (ly1-ly0)/scale) / 2
+	var px [4]uint8
+	for y := int(ly0); y < int(ly1); y++ {
+		for x := int(lx0); x < int(lx1); x++ {
+			c := roundedRectCoverage(float64(x)+0.5, float64(y)+0.5, lx0, ly0, lx1, ly1, w*0.03)
+			if c <= 0 {
+				continue
+			}
+			sx := offX + (float64(x)+0.5-lx0)/scale - 0.5
+			sy := offY + (float64(y)+0.5-ly0)/scale - 0.5
+			sampleBilinear(src, sx, sy, px[:])
+			blendPixel(shell, x, y, [3]uint8{px[0], px[1], px[2]}, c)
+		}
+	}
+
+	// Window the reels show through, and the base with its guide holes
+	fillRoundedRect(shell, w*0.24, h*0.30, w*0.76, h*0.54, h*0.12, cassetteWindow)
+	fillRoundedRect(shell, w*0.2, h*0.74, w*0.8, h*0.98, h*0.04, cassetteBase)
+	fillCircle(shell, w*0.36, h*0.86, h*0.03, cassetteWindow)
+	fillCircle(shell, w*0.64, h*0.86, h*0.03, cassetteWindow)
+	return shell
+}
+
+// frame draws both reels turned index/total of a revolution
+func (cassetteAnimation) frame(prepared *image.RGBA, index int, total int) image.Image {
+	img := cloneRGBA(prepared)
+	w := float64(img.Rect.Dx())
+	h := float64(img.Rect.Dy())
+	angle := 2 * math.Pi * float64(index) / float64(total)
+	for _, cx := range []float64{w * (0.5 - cassetteReelX), w * (0.5 + cassetteReelX)} {
+		drawCassetteReel(img, cx, h*cassetteReelY, h*cassetteHubRadius, angle)
+	}
+	return img
+}
+
+func (cassetteAnimation) maxFrames(cfg Config) int {
+	return cassetteFrames
+}
+
+func (cassetteAnimation) cycle(cfg Config) time.Duration {
+	return cassetteCycle
+}
+
+// drawCassetteReel draws a hub: a white ring with six teeth pointing inward
+func drawCassetteReel(img *image.RGBA, cx, cy, radius, angle float64) {
+	fillCircle(img, cx, cy, radius, cassetteHub)
+	fillCircle(img, cx, cy, radius*0.62, cassetteWindow)
+
+	toothWidth := radius * 0.2
+	for k := 0; k < 6; k++ {
+		a := angle + float64(k)*math.Pi/3
+		ax, ay := cx+radius*0.3*math.Cos(a), cy+radius*0.3*math.Sin(a)
+		bx, by := cx+radius*0.62*math.Cos(a), cy+radius*0.62*math.Sin(a)
+		for y := int(cy - radius); y <= int(cy+radius); y++ {
+			for x := int(cx - radius); x <= int(cx+radius); x++ {
+				d := distanceToSegment(float64(x)+0.5, float64(y)+0.5, ax, ay, bx, by)
+				if c := clamp01(toothWidth/2 - d + 0.5); c > 0 {
+					blendPixel(img, x, y, cassetteHub, c)
+				}
+			}
+		}
+	}
+}
+
+// cdAnimation turns the artwork into a compact disc with an iridescent sheen
+// sweeping around it (artwork.animation: cd)
+type cdAnimation struct{}
+
+// prepare crops the artwork to a disc with a center hole and a clear hub
+func (cdAnimation) prepare(img image.Image, cfg Config) *image.RGBA {
+	disc := cropToCircle(img).(*image.RGBA)
+	size := disc.Rect.Dx()
+	radius := float64(size) / 2
+	hub := [3]uint8{205, 210, 218}
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dist := math.Hypot(float64(x)+0.5-radius, float64(y)+0.5-radius)
+			if dist > cdHubRadius*radius+0.5 {
+				continue
+			}
+			// Clear plastic hub, then the hole punched through it
+			blendPixel(disc, x, y, hub, 0.8*clamp01(cdHubRadius*radius-dist+0.5))
+			if c := clamp01(cdHoleRadius*radius - dist + 0.5); c > 0 {
+				i := disc.PixOffset(x, y)
+				for ch := 0; ch < 4; ch++ {
+					disc.Pix[i+ch] = uint8(float64(disc.Pix[i+ch]) * (1 - c))
+				}
+			}
+		}
+	}
+	return disc
+}
+
+// frame adds rainbow streaks on the data side, rotated index/total of the way
+// around the disc
+func (cdAnimation) frame(prepared *image.RGBA, index int, total int) image.Image {
+	img := cloneRGBA(prepared)
+	size := img.Rect.Dx()
+	radius := float64(size) / 2
+	phase := 2 * math.Pi * float64(index) / float64(total)
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dx, dy := float64(x)+0.5-radius, float64(y)+0.5-radius
+			r := math.Hypot(dx, dy) / radius
+			if r <= cdHubRadius || r >= 1 {
+				continue
+			}
+			theta := math.Atan2(dy, dx)
+			// Two opposite streaks, like light reflecting off the tracks
+			streak := math.Max(0, math.Cos(2*(theta-phase)))
+			intensity := cdSheen * math.Pow(streak, 6)
+			if intensity <= 0.01 {
+				continue
+			}
+			hue := math.Mod(r*2.5+theta/(2*math.Pi)+1, 1)
+			i := img.PixOffset(x, y)
+			px := img.Pix[i : i+4 : i+4]
+			rgb := hsvToRGB(hue, 0.55, 1)
+			// Blend toward the sheen color at the pixel's own alpha
+			// (premultiplied), so the anti-aliased rim stays intact
+			alpha := float64(px[3]) / 255
+			for c := 0; c < 3; c++ {
+				px[c] = uint8(float64(px[c])*(1-intensity) + float64(rgb[c])*alpha*intensity + 0.5)
+			}
+		}
+	}
+	return img
+}
+
+func (cdAnimation) maxFrames(cfg Config) int {
+	return cdFrames
+}
+
+func (cdAnimation) cycle(cfg Config) time.Duration {
+	return cdCycle
+}
+
+// hsvToRGB converts a color from HSV (all components 0-1) to RGB
+func hsvToRGB(h, s, v float64) [3]uint8 {
+	h = math.Mod(h, 1) * 6
+	sector := int(h)
+	f := h - float64(sector)
+	p := v * (1 - s)
+	q := v * (1 - s*f)
+	t := v * (1 - s*(1-f))
+
+	var r, g, b float64
+	switch sector {
+	case 0:
+		r, g, b = v, t, p
+	case 1:
+		r, g, b = q, v, p
+	case 2:
+		r, g, b = p, v, t
+	case 3:
+		r, g, b = p, q, v
+	case 4:
+		r, g, b = t, p, v
+	default:
+		r, g, b = v, p, q
+	}
+	return [3]uint8{uint8(r*255 + 0.5), uint8(g*255 + 0.5), uint8(b*255 + 0.5)}
+}
+
+// kenBurnsAnimation slowly zooms into the artwork and back out, drifting
+// toward its upper right (artwork.animation: kenburns)
+type kenBurnsAnimation struct{}
+
+func (kenBurnsAnimation) prepare(img image.Image, cfg Config) *image.RGBA {
+	return toRGBA(img)
+}
+
+// frame crops a zoomed-in window of the artwork back to full size
+func (kenBurnsAnimation) frame(prepared *image.RGBA, index int, total int) image.Image {
+	zoom := 1 + kenBurnsZoom*loopWave(index, total)
+	w, h := float64(prepared.Rect.Dx()), float64(prepared.Rect.Dy())
+	// Pan as far as the zoom allows without showing past the edges
+	maxPan := (1 - 1/zoom) / 2
+	centerX := w/2 + maxPan*w*0.8
+	centerY := h/2 - maxPan*h*0.6
+	return resample(prepared, func(x, y float64) (float64, float64) {
+		return centerX + (x-w/2)/zoom, centerY + (y-h/2)/zoom
+	})
+}
+
+func (kenBurnsAnimation) maxFrames(cfg Config) int {
+	return kenBurnsFrames
+}
+
+func (kenBurnsAnimation) cycle(cfg Config) time.Duration {
+	return kenBurnsCycle
+}
+
+// pulseAnimation makes the artwork gently breathe, shrinking and dimming a
+// little and back (artwork.animation: pulse)
+type pulseAnimation struct{}
+
+func (pulseAnimation) prepare(img image.Image, cfg Config) *image.RGBA {
+	return toRGBA(img)
+}
+
+func (pulseAnimation) frame(prepared *image.RGBA, index int, total int) image.Image {
+	wave := loopWave(index, total)
+	scale := 1 - pulseScale*wave
+	w, h := float64(prepared.Rect.Dx()), float64(prepared.Rect.Dy())
+	img := resample(prepared, func(x, y float64) (float64, float64) {
+		return w/2 + (x-w/2)/scale, h/2 + (y-h/2)/scale
+	})
+	dim := 1 - pulseDim*wave
+	for i := 0; i < len(img.Pix); i += 4 {
+		scalePixel(img.Pix[i:i+4:i+4], dim)
+	}
+	return img
+}
+
+func (pulseAnimation) maxFrames(cfg Config) int {
+	return pulseFrames
+}
+
+func (pulseAnimation) cycle(cfg Config) time.Duration {
+	return pulseCycle
+}
+
+// resample builds an image the size of src where each pixel samples src
+// (bilinearly) at the position mapped from the pixel's center
+func resample(src *image.RGBA, mapping func(x, y float64) (float64, float64)) *image.RGBA {
+	dst := image.NewRGBA(src.Rect)
+	for y := 0; y < dst.Rect.Dy(); y++ {
+		for x := 0; x < dst.Rect.Dx(); x++ {
+			sx, sy := mapping(float64(x)+0.5, float64(y)+0.5)
+			sampleBilinear(src, sx-0.5, sy-0.5, dst.Pix[dst.PixOffset(x, y):])
+		}
+	}
+	return dst
+}
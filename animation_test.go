@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestAnimationName tests resolving artwork.animation and the older vinyl_mode
+func TestAnimationName(t *testing.T) {
+	tests := []struct {
+		name      string
+		animation string
+		vinylMode bool
+		expected  string
+	}{
+		{"default", "none", false, "none"},
+		{"unset", "", false, "none"},
+		{"legacy vinyl_mode", "none", true, "vinyl"},
+		{"explicit animation wins", "cassette", true, "cassette"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{}
+			cfg.Artwork.Animation = tt.animation
+			cfg.Artwork.VinylMode = tt.vinylMode
+			if got := animationName(cfg); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+			if (activeAnimation(cfg) == nil) != (tt.expected == animationNone) {
+				t.Errorf("activeAnimation disagrees with animationName %s", tt.expected)
+			}
+		})
+	}
+}
+
+// TestNextAnimationName tests that 'v' visits every animation and wraps around
+func TestNextAnimationName(t *testing.T) {
+	seen := make(map[string]bool)
+	name := animationNone
+	for i := 0; i < len(animationOrder); i++ {
+		name = nextAnimationName(name)
+		seen[name] = true
+	}
+	if name != animationNone {
+		t.Errorf("Expected to wrap around to none, got %s", name)
+	}
+	for n := range animations {
+		if !seen[n] {
+			t.Errorf("Animation %s is never reached by cycling", n)
+		}
+	}
+	if got := nextAnimationName("bogus"); got != animationNone {
+		t.Errorf("Expected unknown animation to reset to none, got %s", got)
+	}
+}
+
+// TestAnimationFrames tests every animation renders distinct frames without
+// touching the prepared image (frames render concurrently from it)
+func TestAnimationFrames(t *testing.T) {
+	img := generateGradientImage(60, 60, color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255})
+
+	for name, anim := range animations {
+		t.Run(name, func(t *testing.T) {
+			cfg := Config{}
+			cfg.Artwork.WidthPixels = 60
+			cfg.Artwork.Animation = name
+			cfg.Artwork.VinylRPM = 10
+			cfg.Artwork.VinylFrames = 90
+
+			prepared := prepareArtwork(img, 0, cfg).(*image.RGBA)
+			before := append([]uint8(nil), prepared.Pix...)
+
+			total := anim.maxFrames(cfg)
+			if total < animMinCoarseFrames {
+				t.Fatalf("Expected at least %d frames, got %d", animMinCoarseFrames, total)
+			}
+			if anim.cycle(cfg) <= 0 {
+				t.Fatalf("Expected a positive loop duration")
+			}
+
+			first := anim.frame(prepared, 0, total)
+			middle := anim.frame(prepared, total/4, total)
+			if !first.Bounds().Eq(middle.Bounds()) {
+				t.Errorf("Frame size changed: %v vs %v", first.Bounds(), middle.Bounds())
+			}
+			if bytes.Equal(toRGBA(first).Pix, toRGBA(middle).Pix) {
+				t.Error("Expected frames at different points of the loop to differ")
+			}
+			if !bytes.Equal(prepared.Pix, before) {
+				t.Error("Expected frame to leave the prepared image untouched")
+			}
+		})
+	}
+}
+
+// panickyAnimation is vinyl, except odd frames panic
+type panickyAnimation struct{ vinylAnimation }
+
+func (p panickyAnimation) frame(prepared *image.RGBA, index int, total int) image.Image {
+	if index%2 == 1 {
+		panic("bad frame")
+	}
+	return p.vinylAnimation.frame(prepared, index, total)
+}
+
+// TestAnimationWorkerPanic tests a frame that panics doesn't crash the job,
+// and is filled with a neighbouring frame so playback can still start
+func TestAnimationWorkerPanic(t *testing.T) {
+	animations["panicky"] = panickyAnimation{}
+	defer delete(animations, "panicky")
+
+	var raw bytes.Buffer
+	assertNoError(t, png.Encode(&raw, generateTestImage(40, 40, color.RGBA{200, 100, 50, 255})))
+	cfg := Config{}
+	cfg.Artwork.WidthPixels = 40
+	cfg.Artwork.Animation = "panicky"
+	cfg.Artwork.VinylRPM = 10
+	cfg.Artwork.VinylFrames = 36
+	cfg.Artwork.VinylMemoryMB = 64
+	cfg.Artwork.Transmission = kittyTransmitDirect
+
+	t.Run("progressive", func(t *testing.T) {
+		ch := make(chan tea.Msg, 64)
+		renderAnimationFrames(context.Background(), ch, 1, "t", raw.Bytes(), cfg, false)
+		var frames []string
+		for msg := range ch {
+			frame := msg.(animationFrameMsg)
+			if frames == nil {
+				frames = make([]string, frame.total)
+			}
+			frames[frame.index] = frame.frame
+		}
+		if len(frames) < 2 {
+			t.Fatalf("Expected frames, got %d", len(frames))
+		}
+		if step := frameCoarseStep(frames); step != 1 {
+			t.Errorf("Expected every frame filled, got step %d", step)
+		}
+		if frames[1] != frames[0] && frames[1] != frames[2%len(frames)] {
+			t.Error("Expected the failed frame to repeat a neighbour")
+		}
+	})
+
+	t.Run("native", func(t *testing.T) {
+		ch := make(chan tea.Msg, 64)
+		renderAnimationFrames(context.Background(), ch, 1, "t", raw.Bytes(), cfg, true)
+		var uploads int
+		for msg := range ch {
+			if _, ok := msg.(animationUploadMsg); ok {
+				uploads++
+			}
+		}
+		if uploads != 1 {
+			t.Errorf("Expected the animation upload, got %d", uploads)
+		}
+	})
+}
+
+// TestNearestRenderedFrame tests failed frames are filled from the closest
+// rendered one, wrapping around the loop
+func TestNearestRenderedFrame(t *testing.T) {
+	failed := []bool{false, true, true, false, true, true}
+	assertEqual(t, nearestRenderedFrame(failed, 1), 0, "nearest to frame 1")
+	assertEqual(t, nearestRenderedFrame(failed, 2), 3, "nearest to frame 2")
+	assertEqual(t, nearestRenderedFrame(failed, 4), 3, "nearest to frame 4")
+	assertEqual(t, nearestRenderedFrame(failed, 5), 0, "nearest to frame 5")
+}
+
+// TestAnimationKeyCycles tests that 'v' switches to the next animation and
+// drops frames rendered for the previous one
+func TestAnimationKeyCycles(t *testing.T) {
+	original := config.Get()
+	defer config.Set(original)
+
+	cfg := Config{}
+	cfg.Artwork.Animation = "vinyl"
+	config.Set(cfg)
+
+	m := model{
+		animFrameCache: []string{"a", "b"},
+		animStep:       1,
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+
+	if got := animationName(config.Get()); got != "cassette" {
+		t.Errorf("Expected cassette after vinyl, got %s", got)
+	}
+	if updated.(model).animationReady() {
+		t.Error("Expected previous animation's frames to be dropped")
+	}
+}
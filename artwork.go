@@ -2,10 +2,10 @@ package main
 
 import (
 	"bytes"
-	"encoding/base64"
+	"context"
 	"fmt"
 	"image"
-	"image/color"
+	"image/draw"
 	_ "image/gif"
 	_ "image/jpeg"
 	"image/png"
@@ -14,13 +14,13 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/nfnt/resize"
 	_ "golang.org/x/image/webp"
 )
 
 const (
 	// Kitty graphics protocol constants
 	kittyImageID   = 42   // Fixed image ID for artwork display
+	kittyTonearmID = 43   // Fixed image ID for the vinyl tonearm overlay
 	kittyChunkSize = 4096 // Max bytes per chunk in Kitty protocol
 	kittyFormatPNG = 100  // PNG format code for Kitty protocol
 
@@ -29,11 +29,16 @@ const (
 	colorLightnessWeight  = 1.5    // Balance with lightness
 	colorCountWeight      = 1000.0 // Normalize pixel count contribution
 
-	// Color filter thresholds for extractDominantColor
+	// Color filter thresholds for extractDominantColorOn
 	minLightness  = 0.3  // Too dark (unreadable)
 	maxLightness  = 0.85 // Too light (washed out)
 	minSaturation = 0.25 // Too gray (not vibrant)
 	idealMaxLight = 0.7  // Ideal maximum lightness
+
+	// The same thresholds mirrored for light backgrounds
+	lightMinLightness  = 0.15 // Too dark (muddy)
+	lightMaxLightness  = 0.6  // Too light (unreadable)
+	lightIdealMinLight = 0.3  // Ideal minimum lightness
 )
 
 // decodeArtworkData decodes raw image bytes into an image.Image.
@@ -45,6 +50,10 @@ func decodeArtworkData(imgData []byte) (image.Image, error) {
 	}
 
 	img, _, err := image.Decode(bytes.NewReader(imgData))
+	if err != nil && isAnimatedArtwork(imgData) {
+		// x/image/webp can't read animated WebP; decode its first frame ourselves
+		img, err = decodeFirstArtworkFrame(imgData)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode image: %w", err)
 	}
@@ -52,9 +61,16 @@ func decodeArtworkData(imgData []byte) (image.Image, error) {
 	return img, nil
 }
 
-// Extract dominant color from image and convert to hex
-// Uses a sampling approach to find vibrant, light colors suitable for dark backgrounds
-func extractDominantColor(img image.Image) (string, error) {
+// extractDominantColorOn extracts the dominant color from an image as hex.
+// Uses a sampling approach to find vibrant colors readable on background:
+// light ones on dark backgrounds, darker ones on light backgrounds.
+func extractDominantColorOn(img image.Image, background [3]uint8) (string, error) {
+	light := isLightBackground(background)
+	minL, maxL := minLightness, maxLightness
+	if light {
+		minL, maxL = lightMinLightness, lightMaxLightness
+	}
+
 	if img == nil {
 		return "", fmt.Errorf("nil image")
 	}
@@ -133,7 +149,7 @@ func extractDominantColor(img image.Image) (string, error) {
 		}
 
 		// Skip colors that are too dark, too light (near-white), or too unsaturated
-		if lightness < minLightness || lightness > maxLightness || saturation < minSaturation {
+		if lightness < minL || lightness > maxL || saturation < minSaturation {
 			continue
 		}
 
@@ -141,7 +157,13 @@ func extractDominantColor(img image.Image) (string, error) {
 		// Prefer vibrant colors (high saturation) that are reasonably light
 		// Ideal lightness is around 0.5-0.7 (readable but not washed out)
 		lightnessScore := lightness
-		if lightness > idealMaxLight {
+		if light {
+			// Mirrored: prefer darker colors, penalizing very dark ones
+			lightnessScore = 1 - lightness
+			if lightness < lightIdealMinLight {
+				lightnessScore = (1 - lightIdealMinLight) - (lightIdealMinLight - lightness)
+			}
+		} else if lightness > idealMaxLight {
 			// Penalize very light colors
 			lightnessScore = idealMaxLight - (lightness - idealMaxLight)
 		}
@@ -189,38 +211,63 @@ func supportsKittyGraphics() bool {
 	return false
 }
 
-// cropToCircle crops an image to a circle with transparent corners
-func cropToCircle(img image.Image) image.Image {
+// Check if terminal can play Kitty animations (a=f/a=a) by itself. Ghostty,
+// WezTerm and Konsole display static Kitty images but don't animate them.
+func supportsKittyAnimation() bool {
+	return strings.Contains(os.Getenv("TERM"), "kitty") || os.Getenv("KITTY_WINDOW_ID") != ""
+}
+
+// toRGBA returns img as *image.RGBA with bounds starting at (0,0), converting
+// only when needed, so pixel loops can work on the Pix buffer directly
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
 	bounds := img.Bounds()
-	size := bounds.Dx()
-	if bounds.Dy() < size {
-		size = bounds.Dy()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Rect, img, bounds.Min, draw.Src)
+	return rgba
+}
+
+// cropToCircle crops an image to a circle with transparent corners. The edge
+// is anti-aliased: pixels straddling the rim get partial alpha by coverage.
+func cropToCircle(img image.Image) image.Image {
+	src := toRGBA(img)
+	size := src.Rect.Dx()
+	if src.Rect.Dy() < size {
+		size = src.Rect.Dy()
 	}
 
-	// Create a new RGBA image
+	// Create a new RGBA image (starts fully transparent)
 	circle := image.NewRGBA(image.Rect(0, 0, size, size))
 
 	// Calculate center and radius
-	centerX := float64(size) / 2
-	centerY := float64(size) / 2
+	center := float64(size) / 2
 	radius := float64(size) / 2
 
-	// Copy pixels within circle, make corners transparent
 	for y := 0; y < size; y++ {
 		for x := 0; x < size; x++ {
-			// Calculate distance from center
-			dx := float64(x) - centerX
-			dy := float64(y) - centerY
+			// Distance from center to the pixel's center
+			dx := float64(x) + 0.5 - center
+			dy := float64(y) + 0.5 - center
 			distance := math.Sqrt(dx*dx + dy*dy)
 
-			if distance <= radius {
-				// Inside circle - copy pixel from source
-				srcX := bounds.Min.X + x
-				srcY := bounds.Min.Y + y
-				circle.Set(x, y, img.At(srcX, srcY))
-			} else {
-				// Outside circle - transparent
-				circle.Set(x, y, color.Transparent)
+			// Approximate pixel coverage: 1 inside, 0 outside, linear across
+			// the one-pixel band at the rim
+			coverage := radius - distance + 0.5
+			if coverage <= 0 {
+				continue
+			}
+
+			si := src.PixOffset(x, y)
+			di := circle.PixOffset(x, y)
+			if coverage >= 1 {
+				copy(circle.Pix[di:di+4], src.Pix[si:si+4])
+				continue
+			}
+			// RGBA is alpha-premultiplied, so scale every channel
+			for c := 0; c < 4; c++ {
+				circle.Pix[di+c] = uint8(float64(src.Pix[si+c])*coverage + 0.5)
 			}
 		}
 	}
@@ -228,14 +275,15 @@ func cropToCircle(img image.Image) image.Image {
 	return circle
 }
 
-// rotateImage rotates an image by the given angle (in degrees)
+// rotateImage rotates an image by the given angle (in degrees) around its
+// center, sampling the source bilinearly for smooth edges
 func rotateImage(img image.Image, angleDegrees float64) image.Image {
-	bounds := img.Bounds()
-	width := bounds.Dx()
-	height := bounds.Dy()
+	src := toRGBA(img)
+	width := src.Rect.Dx()
+	height := src.Rect.Dy()
 
 	// Create new image for rotated result
-	rotated := image.NewRGBA(bounds)
+	rotated := image.NewRGBA(image.Rect(0, 0, width, height))
 
 	// Convert angle to radians
 	angleRad := angleDegrees * math.Pi / 180.0
@@ -249,122 +297,126 @@ func rotateImage(img image.Image, angleDegrees float64) image.Image {
 	// Rotate each pixel
 	for y := 0; y < height; y++ {
 		for x := 0; x < width; x++ {
-			// Translate to origin
-			tx := float64(x) - centerX
-			ty := float64(y) - centerY
+			// Translate pixel center to origin
+			tx := float64(x) + 0.5 - centerX
+			ty := float64(y) + 0.5 - centerY
 
-			// Rotate (reverse rotation for sampling)
-			srcX := tx*cosAngle + ty*sinAngle + centerX
-			srcY := -tx*sinAngle + ty*cosAngle + centerY
+			// Rotate (reverse rotation for sampling), back to pixel coordinates
+			srcX := tx*cosAngle + ty*sinAngle + centerX - 0.5
+			srcY := -tx*sinAngle + ty*cosAngle + centerY - 0.5
 
-			// Sample from source image
-			if srcX >= 0 && srcX < float64(width) && srcY >= 0 && srcY < float64(height) {
-				rotated.Set(x, y, img.At(int(srcX), int(srcY)))
-			} else {
-				rotated.Set(x, y, color.Transparent)
-			}
+			sampleBilinear(src, srcX, srcY, rotated.Pix[rotated.PixOffset(x, y):])
 		}
 	}
 
 	return rotated
 }
 
-// Process and encode artwork for Kitty graphics protocol
-// If vinyl mode is enabled and rotationAngle > 0, rotates and crops to circle
-func encodeArtworkForKitty(img image.Image, rotationAngle int, frameCount int) (string, error) {
-	if img == nil {
-		return "", fmt.Errorf("nil image")
+// sampleBilinear writes the bilinear interpolation of the four source pixels
+// around (x, y) into dst[0:4]. Pixels outside the image count as transparent.
+func sampleBilinear(src *image.RGBA, x, y float64, dst []uint8) {
+	x0 := int(math.Floor(x))
+	y0 := int(math.Floor(y))
+	fx := x - float64(x0)
+	fy := y - float64(y0)
+
+	width := src.Rect.Dx()
+	height := src.Rect.Dy()
+
+	var acc [4]float64
+	weights := [4]float64{(1 - fx) * (1 - fy), fx * (1 - fy), (1 - fx) * fy, fx * fy}
+	points := [4][2]int{{x0, y0}, {x0 + 1, y0}, {x0, y0 + 1}, {x0 + 1, y0 + 1}}
+	for i, p := range points {
+		if weights[i] == 0 || p[0] < 0 || p[1] < 0 || p[0] >= width || p[1] >= height {
+			continue
+		}
+		si := src.PixOffset(p[0], p[1])
+		for c := 0; c < 4; c++ {
+			acc[c] += float64(src.Pix[si+c]) * weights[i]
+		}
 	}
+	for c := 0; c < 4; c++ {
+		dst[c] = uint8(acc[c] + 0.5)
+	}
+}
 
-	// Get config snapshot for this operation
-	cfg := config.Get()
-
-	// Resize maintaining aspect ratio - keep it reasonable for terminal display
-	// We'll let Kitty handle the final sizing based on cell dimensions
-	resized := resize.Resize(uint(cfg.Artwork.WidthPixels), 0, img, resize.Lanczos3)
+// prepareArtwork resizes and filters artwork for display (cached per
+// artworkHash, see baseArtworkCache) and lets the active animation (if any)
+// prepare its base image. Filters run before the animation, so e.g. vinyl's
+// circle crop and rotation apply to the filtered artwork. Frames are made
+// from the result.
+func prepareArtwork(img image.Image, artworkHash uint64, cfg Config) image.Image {
+	prepared := baseArtwork.get(img, artworkHash, cfg)
 
-	// Apply vinyl effects if enabled
-	processedImg := resized
-	if cfg.Artwork.VinylMode {
-		// Crop to circle first
-		processedImg = cropToCircle(processedImg)
+	if anim := activeAnimation(cfg); anim != nil {
+		return anim.prepare(prepared, cfg)
+	}
+	return prepared
+}
 
-		// Rotate based on angle - calculate degrees per frame dynamically
-		if rotationAngle > 0 {
-			degreesPerFrame := 360.0 / float64(frameCount)
-			angleDegrees := float64(rotationAngle) * degreesPerFrame
-			processedImg = rotateImage(processedImg, angleDegrees)
-		}
+// encodeFramePNG renders frame index of total with anim (nil for static
+// artwork) and encodes it as PNG
+func encodeFramePNG(anim artworkAnimation, prepared image.Image, index int, total int) ([]byte, error) {
+	frame := prepared
+	if anim != nil {
+		frame = anim.frame(prepared.(*image.RGBA), index, total)
 	}
 
-	// Encode as PNG
 	var buf bytes.Buffer
-	if err := png.Encode(&buf, processedImg); err != nil {
-		return "", fmt.Errorf("failed to encode PNG: %w", err)
+	if err := png.Encode(&buf, frame); err != nil {
+		return nil, fmt.Errorf("failed to encode PNG: %w", err)
 	}
+	return buf.Bytes(), nil
+}
 
-	// Encode as base64 for Kitty protocol
-	encoded := base64.StdEncoding.EncodeToString(buf.Bytes())
-
-	// Kitty protocol needs chunking for large payloads
-	var result strings.Builder
-
-	// Skip delete in vinyl mode - just overwrite the image in place for smooth animation
-	if !cfg.Artwork.VinylMode {
-		result.WriteString(fmt.Sprintf("\033_Ga=d,d=I,i=%d\033\\", kittyImageID))
+// Process and encode artwork for Kitty graphics protocol, returning the
+// transmit command for kittyImageID (see kittyPlace for displaying it)
+// With an animation active, encodes frame frameIndex of frameCount.
+// artworkHash identifies the raw artwork for caching (0 = don't cache).
+func encodeArtworkForKitty(img image.Image, cfg Config, artworkHash uint64, frameIndex int, frameCount int) (string, error) {
+	if img == nil {
+		return "", fmt.Errorf("nil image")
 	}
 
-	if len(encoded) <= kittyChunkSize {
-		// Small enough to send in one go
-		// Use columns (c) instead of pixels for zoom-independent sizing
-		// Height is auto-calculated to maintain aspect ratio
-		result.WriteString(fmt.Sprintf("\033_Ga=T,f=%d,t=d,i=%d,c=%d,C=1;%s\033\\", kittyFormatPNG, kittyImageID, cfg.Artwork.WidthColumns, encoded))
-	} else {
-		// Need to chunk the data
-		for i := 0; i < len(encoded); i += kittyChunkSize {
-			end := i + kittyChunkSize
-			if end > len(encoded) {
-				end = len(encoded)
-			}
-			chunk := encoded[i:end]
-
-			if i == 0 {
-				// First chunk with columns-based sizing
-				result.WriteString(fmt.Sprintf("\033_Ga=T,f=%d,t=d,i=%d,c=%d,C=1,m=1;%s\033\\", kittyFormatPNG, kittyImageID, cfg.Artwork.WidthColumns, chunk))
-			} else if end < len(encoded) {
-				// Middle chunk
-				result.WriteString(fmt.Sprintf("\033_Gm=1;%s\033\\", chunk))
-			} else {
-				// Last chunk
-				result.WriteString(fmt.Sprintf("\033_Gm=0;%s\033\\", chunk))
-			}
-		}
+	data, err := encodeFramePNG(activeAnimation(cfg), prepareArtwork(img, artworkHash, cfg), frameIndex, frameCount)
+	if err != nil {
+		return "", err
 	}
 
-	return result.String(), nil
+	// Upload only (a=t) — View places it with a small a=p command, so the
+	// payload is sent once per image instead of on every render
+	return kittyTransmit(data, kittyImageID, cfg.Artwork.Transmission, kittyOwnerStill)
 }
 
-// processArtwork decodes artwork data once and returns both the extracted color and Kitty-encoded string
-// This is more efficient than calling extractDominantColor and encodeArtworkForKitty separately,
-// as it avoids decoding the image twice
-func processArtwork(artworkData []byte, extractColor bool, rotationAngle int, frameCount int) (color string, encoded string, err error) {
+// processArtwork decodes artwork data once and returns both the extracted palette and Kitty-encoded string
+// This is more efficient than calling extractPalette and encodeArtworkForKitty separately,
+// as it avoids decoding the image twice. ctx is checked between the decode,
+// extract and encode stages, so a track change stops the work early
+// (returning ctx's error).
+func processArtwork(ctx context.Context, cfg Config, artworkData []byte, extractColor bool, frameIndex int, frameCount int) (colors palette, encoded string, err error) {
 	// Decode the image once
 	img, err := decodeArtworkData(artworkData)
 	if err != nil {
-		return "", "", err
+		return palette{}, "", err
+	}
+	if err := ctx.Err(); err != nil {
+		return palette{}, "", err
 	}
 
-	// Extract color if requested
+	// Extract the palette if requested (ui.palette picks the strategy)
 	if extractColor {
-		if c, err := extractDominantColor(img); err == nil && c != "" {
-			color = c
+		if p, err := extractPalette(img, cfg.UI.Palette, backgroundFor(cfg)); err == nil {
+			colors = p
+		}
+		if err := ctx.Err(); err != nil {
+			return palette{}, "", err
 		}
 	}
 
 	// Encode for Kitty protocol
-	if enc, err := encodeArtworkForKitty(img, rotationAngle, frameCount); err == nil && enc != "" {
+	if enc, err := encodeArtworkForKitty(img, cfg, hashBytes(artworkData), frameIndex, frameCount); err == nil && enc != "" {
 		encoded = enc
 	}
 
-	return color, encoded, nil
+	return colors, encoded, nil
 }
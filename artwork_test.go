@@ -2,7 +2,9 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
+	"image"
 	"image/color"
 	"image/png"
 	"os"
@@ -53,12 +55,12 @@ func TestDecodeArtworkData(t *testing.T) {
 	})
 }
 
-// TestExtractDominantColor tests the extractDominantColor function
+// TestExtractDominantColor tests extractDominantColorOn on a dark background
 func TestExtractDominantColor(t *testing.T) {
 	t.Run("solid color image", func(t *testing.T) {
 		// Red image
 		img := generateTestImage(100, 100, color.RGBA{255, 0, 0, 255})
-		color, err := extractDominantColor(img)
+		color, err := extractDominantColorOn(img, darkBackground)
 		assertNoError(t, err)
 
 		if !isValidHexColor(color) {
@@ -72,7 +74,7 @@ func TestExtractDominantColor(t *testing.T) {
 			color.RGBA{0, 0, 255, 255},
 			color.RGBA{0, 255, 0, 255})
 
-		color, err := extractDominantColor(img)
+		color, err := extractDominantColorOn(img, darkBackground)
 		assertNoError(t, err)
 
 		if !isValidHexColor(color) {
@@ -83,7 +85,7 @@ func TestExtractDominantColor(t *testing.T) {
 	t.Run("small image", func(t *testing.T) {
 		// Very small image (edge case)
 		img := generateTestImage(5, 5, color.RGBA{128, 128, 255, 255})
-		color, err := extractDominantColor(img)
+		color, err := extractDominantColorOn(img, darkBackground)
 		assertNoError(t, err)
 
 		if !isValidHexColor(color) {
@@ -92,7 +94,7 @@ func TestExtractDominantColor(t *testing.T) {
 	})
 
 	t.Run("nil image", func(t *testing.T) {
-		_, err := extractDominantColor(nil)
+		_, err := extractDominantColorOn(nil, darkBackground)
 		if err == nil {
 			t.Error("Expected error for nil image")
 		}
@@ -101,7 +103,7 @@ func TestExtractDominantColor(t *testing.T) {
 	t.Run("transparent image", func(t *testing.T) {
 		// Fully transparent image
 		img := generateTestImage(50, 50, color.RGBA{255, 0, 0, 0})
-		_, err := extractDominantColor(img)
+		_, err := extractDominantColorOn(img, darkBackground)
 		// Should handle transparent images gracefully
 		// (might return error or fallback color)
 		if err != nil {
@@ -120,7 +122,7 @@ func TestEncodeArtworkForKitty(t *testing.T) {
 
 	t.Run("valid image", func(t *testing.T) {
 		img := generateTestImage(50, 50, color.RGBA{100, 150, 200, 255})
-		encoded, err := encodeArtworkForKitty(img, 0, 90)
+		encoded, err := encodeArtworkForKitty(img, testConfig, 0, 0, 90)
 		assertNoError(t, err)
 
 		if encoded == "" {
@@ -134,7 +136,7 @@ func TestEncodeArtworkForKitty(t *testing.T) {
 	})
 
 	t.Run("nil image", func(t *testing.T) {
-		_, err := encodeArtworkForKitty(nil, 0, 90)
+		_, err := encodeArtworkForKitty(nil, testConfig, 0, 0, 90)
 		if err == nil {
 			t.Error("Expected error for nil image")
 		}
@@ -143,7 +145,7 @@ func TestEncodeArtworkForKitty(t *testing.T) {
 	t.Run("large image chunks", func(t *testing.T) {
 		// Large image that should trigger chunking
 		img := generateTestImage(800, 800, color.RGBA{100, 150, 200, 255})
-		encoded, err := encodeArtworkForKitty(img, 0, 90)
+		encoded, err := encodeArtworkForKitty(img, testConfig, 0, 0, 90)
 		assertNoError(t, err)
 
 		if encoded == "" {
@@ -176,11 +178,13 @@ func TestProcessArtwork(t *testing.T) {
 	imageData := buf.Bytes()
 
 	t.Run("with color extraction", func(t *testing.T) {
-		color, encoded, err := processArtwork(imageData, true, 0, 90)
+		colors, encoded, err := processArtwork(context.Background(), testConfig, imageData, true, 0, 90)
 		assertNoError(t, err)
 
-		if !isValidHexColor(color) {
-			t.Errorf("Invalid hex color: %s", color)
+		for _, c := range []string{colors.accent, colors.secondary, colors.muted, colors.border} {
+			if !isValidHexColor(c) {
+				t.Errorf("Invalid hex color: %s", c)
+			}
 		}
 
 		if encoded == "" {
@@ -189,11 +193,11 @@ func TestProcessArtwork(t *testing.T) {
 	})
 
 	t.Run("without color extraction", func(t *testing.T) {
-		color, encoded, err := processArtwork(imageData, false, 0, 90)
+		colors, encoded, err := processArtwork(context.Background(), testConfig, imageData, false, 0, 90)
 		assertNoError(t, err)
 
-		if color != "" {
-			t.Error("Expected empty color when extractColor=false")
+		if colors != (palette{}) {
+			t.Error("Expected empty palette when extractColor=false")
 		}
 
 		if encoded == "" {
@@ -202,18 +206,28 @@ func TestProcessArtwork(t *testing.T) {
 	})
 
 	t.Run("invalid data", func(t *testing.T) {
-		_, _, err := processArtwork([]byte("not an image"), true, 0, 90)
+		_, _, err := processArtwork(context.Background(), testConfig, []byte("not an image"), true, 0, 90)
 		if err == nil {
 			t.Error("Expected error for invalid data")
 		}
 	})
 
 	t.Run("empty data", func(t *testing.T) {
-		_, _, err := processArtwork([]byte{}, true, 0, 90)
+		_, _, err := processArtwork(context.Background(), testConfig, []byte{}, true, 0, 90)
 		if err == nil {
 			t.Error("Expected error for empty data")
 		}
 	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		colors, encoded, err := processArtwork(ctx, testConfig, imageData, true, 0, 90)
+		assertEqual(t, err, context.Canceled, "error")
+		if colors != (palette{}) || encoded != "" {
+			t.Error("Expected no result once cancelled")
+		}
+	})
 }
 
 // TestSupportsKittyGraphics tests terminal detection
@@ -256,13 +270,177 @@ func TestSupportsKittyGraphics(t *testing.T) {
 	}
 }
 
+// TestSupportsKittyAnimation tests detection of terminal-side animation support
+func TestSupportsKittyAnimation(t *testing.T) {
+	tests := []struct {
+		name          string
+		term          string
+		windowID      string
+		shouldSupport bool
+	}{
+		{"kitty terminal", "xterm-kitty", "", true},
+		{"kitty window over ssh", "xterm-256color", "1", true},
+		{"ghostty", "xterm-ghostty", "", false},
+		{"konsole", "konsole", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TERM", tt.term)
+			t.Setenv("KITTY_WINDOW_ID", tt.windowID)
+
+			if got := supportsKittyAnimation(); got != tt.shouldSupport {
+				t.Errorf("Expected %v, got %v for TERM=%s", tt.shouldSupport, got, tt.term)
+			}
+		})
+	}
+}
+
+// TestCropToCircleAntialiased tests that the rim blends instead of stepping
+func TestCropToCircleAntialiased(t *testing.T) {
+	img := cropToCircle(generateTestImage(100, 100, color.RGBA{200, 100, 50, 255})).(*image.RGBA)
+
+	if a := img.RGBAAt(50, 50).A; a != 255 {
+		t.Errorf("Expected opaque center, got alpha %d", a)
+	}
+	if a := img.RGBAAt(0, 0).A; a != 0 {
+		t.Errorf("Expected transparent corner, got alpha %d", a)
+	}
+
+	// Walk out from the center along a diagonal: somewhere on the rim a pixel
+	// must be partially covered
+	partial := false
+	for i := 50; i < 100; i++ {
+		if a := img.RGBAAt(i, i).A; a > 0 && a < 255 {
+			partial = true
+			break
+		}
+	}
+	if !partial {
+		t.Error("Expected partially transparent pixels along the rim")
+	}
+}
+
+// TestRotateImage tests bilinear rotation
+func TestRotateImage(t *testing.T) {
+	src := generateGradientImage(64, 64, color.RGBA{0, 0, 0, 255}, color.RGBA{255, 255, 255, 255})
+
+	t.Run("zero degrees is identity", func(t *testing.T) {
+		rotated := rotateImage(src, 0).(*image.RGBA)
+		if !bytes.Equal(rotated.Pix, src.Pix) {
+			t.Error("Expected rotation by 0 degrees to leave pixels unchanged")
+		}
+	})
+
+	t.Run("full turn is identity", func(t *testing.T) {
+		rotated := rotateImage(src, 360).(*image.RGBA)
+		for i := range src.Pix {
+			if d := int(rotated.Pix[i]) - int(src.Pix[i]); d < -1 || d > 1 {
+				t.Fatalf("Pixel byte %d differs by %d after a full turn", i, d)
+			}
+		}
+	})
+}
+
+// TestDrawRecordDetails tests the optional record details
+func TestDrawRecordDetails(t *testing.T) {
+	newRecord := func() *image.RGBA {
+		return cropToCircle(generateTestImage(200, 200, color.RGBA{200, 200, 200, 255})).(*image.RGBA)
+	}
+
+	t.Run("disabled leaves record untouched", func(t *testing.T) {
+		record := newRecord()
+		want := append([]uint8(nil), record.Pix...)
+		drawRecordDetails(record, Config{})
+		if !bytes.Equal(record.Pix, want) {
+			t.Error("Expected no changes with all details disabled")
+		}
+	})
+
+	t.Run("spindle punches a hole", func(t *testing.T) {
+		record := newRecord()
+		cfg := Config{}
+		cfg.Artwork.VinylSpindle = true
+		drawRecordDetails(record, cfg)
+		if a := record.RGBAAt(100, 100).A; a != 0 {
+			t.Errorf("Expected transparent spindle hole, got alpha %d", a)
+		}
+	})
+
+	t.Run("label ring darkens the label edge", func(t *testing.T) {
+		record := newRecord()
+		cfg := Config{}
+		cfg.Artwork.VinylLabel = true
+		drawRecordDetails(record, cfg)
+		x := 100 + int(vinylLabelRadius*100)
+		if r := record.RGBAAt(x, 100).R; r >= 200 {
+			t.Errorf("Expected darkened ring at label edge, got red %d", r)
+		}
+		if r := record.RGBAAt(100, 100).R; r != 200 {
+			t.Errorf("Expected untouched label center, got red %d", r)
+		}
+	})
+
+	t.Run("grooves stay outside the label", func(t *testing.T) {
+		record := newRecord()
+		cfg := Config{}
+		cfg.Artwork.VinylGrooves = true
+		drawRecordDetails(record, cfg)
+		if r := record.RGBAAt(110, 100).R; r != 200 {
+			t.Errorf("Expected untouched label, got red %d", r)
+		}
+		darkened := false
+		for x := 100 + int(vinylLabelRadius*100) + 1; x < 195; x++ {
+			if record.RGBAAt(x, 100).R < 200 {
+				darkened = true
+				break
+			}
+		}
+		if !darkened {
+			t.Error("Expected groove shading outside the label")
+		}
+	})
+}
+
+// TestTonearmAngle tests mapping track progress to tonearm angle
+func TestTonearmAngle(t *testing.T) {
+	tests := []struct {
+		progress float64
+		expected int
+	}{
+		{-0.5, int(tonearmStartAngle)},
+		{0, int(tonearmStartAngle)},
+		{0.5, int((tonearmStartAngle + tonearmEndAngle) / 2)},
+		{1, int(tonearmEndAngle)},
+		{2, int(tonearmEndAngle)},
+	}
+
+	for _, tt := range tests {
+		if got := tonearmAngle(tt.progress); got != tt.expected {
+			t.Errorf("tonearmAngle(%v) = %d, expected %d", tt.progress, got, tt.expected)
+		}
+	}
+}
+
+// TestRenderTonearm tests that the overlay is mostly transparent
+func TestRenderTonearm(t *testing.T) {
+	img := renderTonearm(100, int(tonearmStartAngle))
+
+	if a := img.RGBAAt(5, 95).A; a != 0 {
+		t.Errorf("Expected transparent bottom-left corner, got alpha %d", a)
+	}
+	if a := img.RGBAAt(94, 6).A; a != 255 {
+		t.Errorf("Expected opaque pivot, got alpha %d", a)
+	}
+}
+
 // BenchmarkExtractDominantColor benchmarks color extraction
 func BenchmarkExtractDominantColor(b *testing.B) {
 	img := generateTestImage(300, 300, color.RGBA{100, 150, 200, 255})
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		extractDominantColor(img)
+		extractDominantColorOn(img, darkBackground)
 	}
 }
 
@@ -278,7 +456,7 @@ func BenchmarkEncodeArtworkForKitty(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		encodeArtworkForKitty(img, 0, 90)
+		encodeArtworkForKitty(img, testConfig, 0, 0, 90)
 	}
 }
 
@@ -299,14 +477,14 @@ func BenchmarkProcessArtwork(b *testing.B) {
 	b.Run("with color extraction", func(b *testing.B) {
 		b.ResetTimer()
 		for i := 0; i < b.N; i++ {
-			processArtwork(imageData, true, 0, 90)
+			processArtwork(context.Background(), testConfig, imageData, true, 0, 90)
 		}
 	})
 
 	b.Run("without color extraction", func(b *testing.B) {
 		b.ResetTimer()
 		for i := 0; i < b.N; i++ {
-			processArtwork(imageData, false, 0, 90)
+			processArtwork(context.Background(), testConfig, imageData, false, 0, 90)
 		}
 	})
 }
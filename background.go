@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/muesli/cancelreader"
+)
+
+const (
+	backgroundAuto  = "auto"
+	backgroundDark  = "dark"
+	backgroundLight = "light"
+
+	// backgroundQueryTimeout bounds the wait for the terminal to answer
+	backgroundQueryTimeout = 200 * time.Millisecond
+)
+
+var (
+	// Stand-ins for ui.background dark/light, and for auto when the terminal
+	// doesn't say
+	darkBackground  = [3]uint8{0x1e, 0x1e, 0x1e}
+	lightBackground = [3]uint8{0xfa, 0xfa, 0xfa}
+
+	// detectedBackground is what the terminal reported (OSC 11): at startup,
+	// and again when ui.background switches to auto. Guarded by backgroundMu,
+	// as artwork processing reads it in the background.
+	backgroundMu         sync.RWMutex
+	detectedBackground   [3]uint8
+	backgroundWasQueried bool
+)
+
+// backgroundFor returns the background color the UI should be readable on
+func backgroundFor(cfg Config) [3]uint8 {
+	switch cfg.UI.Background {
+	case backgroundLight:
+		return lightBackground
+	case backgroundDark:
+		return darkBackground
+	}
+	backgroundMu.RLock()
+	defer backgroundMu.RUnlock()
+	if backgroundWasQueried {
+		return detectedBackground
+	}
+	return darkBackground
+}
+
+// isLightBackground reports whether dark text reads better than light text
+func isLightBackground(bg [3]uint8) bool {
+	return contrastRatio([3]uint8{0, 0, 0}, bg) > contrastRatio([3]uint8{255, 255, 255}, bg)
+}
+
+// uiColors are the fixed ANSI colors View uses where no palette role applies
+type uiColors struct {
+	track string // Unfilled part of the progress bar
+	muted string // Placeholder text and the help hint
+	dim   string // Secondary placeholder text
+	error string // Error messages
+}
+
+var (
+	darkUIColors  = uiColors{track: "15", muted: "240", dim: "245", error: "203"}
+	lightUIColors = uiColors{track: "0", muted: "245", dim: "242", error: "160"}
+)
+
+// uiColorsFor picks the fixed UI colors for the background
+func uiColorsFor(cfg Config) uiColors {
+	if isLightBackground(backgroundFor(cfg)) {
+		return lightUIColors
+	}
+	return darkUIColors
+}
+
+// detectTerminalBackground asks the terminal for its background color with
+// OSC 11, remembering the answer for backgroundFor. Must run while Bubble
+// Tea isn't reading the terminal: before the program starts, or through
+// backgroundQuery. A DA1 query goes out right behind it: every terminal
+// answers that, so terminals that ignore OSC 11 don't cost the whole
+// timeout.
+func detectTerminalBackground() {
+	if !term.IsTerminal(os.Stdout.Fd()) {
+		return
+	}
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return
+	}
+	defer func() { _ = tty.Close() }()
+
+	state, err := term.MakeRaw(tty.Fd())
+	if err != nil {
+		return
+	}
+	defer func() { _ = term.Restore(tty.Fd(), state) }()
+
+	// Read deadlines need a pollable tty, and macOS can't poll /dev/tty, so
+	// the read is cancelled once the time is up instead (cancelreader uses
+	// select there, as Bubble Tea does)
+	reader, err := cancelreader.NewReader(tty)
+	if err != nil {
+		return
+	}
+	defer func() { _ = reader.Close() }()
+	timeout := time.AfterFunc(backgroundQueryTimeout, func() { reader.Cancel() })
+	defer timeout.Stop()
+
+	if _, err := tty.WriteString("\x1b]11;?\x1b\\\x1b[c"); err != nil {
+		return
+	}
+
+	var reply []byte
+	buf := make([]byte, 256)
+	for {
+		n, err := reader.Read(buf)
+		reply = append(reply, buf[:n]...)
+		if err != nil || daReplyComplete(reply) {
+			break
+		}
+	}
+	if rgb, ok := parseOSC11Reply(reply); ok {
+		backgroundMu.Lock()
+		detectedBackground = rgb
+		backgroundWasQueried = true
+		backgroundMu.Unlock()
+	}
+}
+
+// backgroundQuery runs detectTerminalBackground while the program is
+// running, through tea.Exec so Bubble Tea lets go of the terminal first
+// (its input reader would take the reply otherwise). A different answer is
+// picked up like a config reload.
+type backgroundQuery struct{}
+
+func (backgroundQuery) Run() error {
+	before := backgroundFor(Config{})
+	detectTerminalBackground()
+	if backgroundFor(Config{}) != before {
+		notifyConfigChange()
+	}
+	return nil
+}
+
+func (backgroundQuery) SetStdin(io.Reader)  {}
+func (backgroundQuery) SetStdout(io.Writer) {}
+func (backgroundQuery) SetStderr(io.Writer) {}
+
+// daReplyComplete reports whether reply contains the DA1 answer (ESC [ ? … c)
+func daReplyComplete(reply []byte) bool {
+	i := bytes.Index(reply, []byte("\x1b[?"))
+	return i >= 0 && bytes.IndexByte(reply[i:], 'c') >= 0
+}
+
+// parseOSC11Reply extracts the color from an OSC 11 answer such as
+// ESC ] 11 ; rgb:1e1e/1e1e/1e1e BEL. Components have 1-4 hex digits.
+func parseOSC11Reply(reply []byte) ([3]uint8, bool) {
+	s := string(reply)
+	start := strings.Index(s, "\x1b]11;rgb:")
+	if start < 0 {
+		return [3]uint8{}, false
+	}
+	s = s[start+len("\x1b]11;rgb:"):]
+	if end := strings.IndexAny(s, "\x07\x1b"); end >= 0 {
+		s = s[:end]
+	} else {
+		return [3]uint8{}, false
+	}
+
+	parts := strings.Split(s, "/")
+	if len(parts) != 3 {
+		return [3]uint8{}, false
+	}
+	var rgb [3]uint8
+	for i, part := range parts {
+		if len(part) < 1 || len(part) > 4 {
+			return [3]uint8{}, false
+		}
+		v, err := strconv.ParseUint(part, 16, 16)
+		if err != nil {
+			return [3]uint8{}, false
+		}
+		// Scale from the component's own range (e.g. 0-ffff) to 0-255
+		max := uint64(1)<<(4*len(part)) - 1
+		rgb[i] = uint8((v*255 + max/2) / max)
+	}
+	return rgb, true
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"image/color"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TestParseOSC11Reply verifies background replies in the formats terminals use
+func TestParseOSC11Reply(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+		want  [3]uint8
+		ok    bool
+	}{
+		{"four digits, ST", "\x1b]11;rgb:1e1e/1e1e/1e1e\x1b\\\x1b[?62;c", [3]uint8{0x1e, 0x1e, 0x1e}, true},
+		{"two digits, BEL", "\x1b]11;rgb:fa/f0/e6\x07", [3]uint8{0xfa, 0xf0, 0xe6}, true},
+		{"one digit", "\x1b]11;rgb:f/0/8\x07", [3]uint8{255, 0, 136}, true},
+		{"after other output", "junk\x1b]11;rgb:ffff/ffff/ffff\x07", [3]uint8{255, 255, 255}, true},
+		{"DA1 only", "\x1b[?62;22c", [3]uint8{}, false},
+		{"unterminated", "\x1b]11;rgb:ffff/ffff/ffff", [3]uint8{}, false},
+		{"two components", "\x1b]11;rgb:ff/ff\x07", [3]uint8{}, false},
+		{"not hex", "\x1b]11;rgb:zz/00/00\x07", [3]uint8{}, false},
+		{"too many digits", "\x1b]11;rgb:fffff/0/0\x07", [3]uint8{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseOSC11Reply([]byte(tt.reply))
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("parseOSC11Reply(%q) = %v, %v; want %v, %v", tt.reply, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+
+	if daReplyComplete([]byte("\x1b]11;rgb:0/0/0\x07\x1b[?6")) {
+		t.Error("partial DA1 reply should not be complete")
+	}
+	if !daReplyComplete([]byte("\x1b]11;rgb:0/0/0\x07\x1b[?62;22c")) {
+		t.Error("DA1 reply should be complete")
+	}
+}
+
+// TestBackgroundFor verifies ui.background overrides detection, and the fixed
+// UI colors follow the background
+func TestBackgroundFor(t *testing.T) {
+	defer func(bg [3]uint8, queried bool) {
+		detectedBackground, backgroundWasQueried = bg, queried
+	}(detectedBackground, backgroundWasQueried)
+
+	cfg := Config{}
+	cfg.UI.Background = backgroundAuto
+	backgroundWasQueried = false
+	assertEqual(t, backgroundFor(cfg), darkBackground, "auto without a reply")
+
+	detectedBackground, backgroundWasQueried = [3]uint8{0xff, 0xff, 0xee}, true
+	assertEqual(t, backgroundFor(cfg), [3]uint8{0xff, 0xff, 0xee}, "auto with a reply")
+	assertEqual(t, uiColorsFor(cfg), lightUIColors, "UI colors on detected light background")
+
+	cfg.UI.Background = backgroundDark
+	assertEqual(t, backgroundFor(cfg), darkBackground, "dark override")
+	assertEqual(t, uiColorsFor(cfg), darkUIColors, "UI colors on dark background")
+
+	cfg.UI.Background = backgroundLight
+	assertEqual(t, backgroundFor(cfg), lightBackground, "light override")
+
+	if isLightBackground(darkBackground) || !isLightBackground(lightBackground) {
+		t.Error("isLightBackground misclassified the stand-in backgrounds")
+	}
+}
+
+// TestExtractDominantColorOnLight verifies light backgrounds get a darker
+// accent than dark ones
+func TestExtractDominantColorOnLight(t *testing.T) {
+	img := generateGradientImage(40, 40, color.RGBA{60, 0, 0, 255}, color.RGBA{255, 200, 200, 255})
+
+	onDark, err := extractDominantColorOn(img, darkBackground)
+	assertNoError(t, err)
+	onLight, err := extractDominantColorOn(img, lightBackground)
+	assertNoError(t, err)
+
+	dark, _ := parseHexColor(onDark)
+	light, _ := parseHexColor(onLight)
+	if relativeLuminance(light) >= relativeLuminance(dark) {
+		t.Errorf("accent on light background %s should be darker than on dark %s", onLight, onDark)
+	}
+}
+
+// TestBackgroundRedetect verifies switching ui.background to auto asks the
+// terminal again, and only on the switch
+func TestBackgroundRedetect(t *testing.T) {
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+	defer config.Set(config.Get())
+	defer drainConfigChange()
+	cfg := layoutTestConfig(layoutCard)
+	cfg.UI.Background = backgroundAuto
+	config.Set(cfg)
+
+	updated, cmd := model{backgroundSetting: backgroundDark}.Update(configReloadMsg{})
+	assertEqual(t, updated.(model).backgroundSetting, backgroundAuto, "setting followed")
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) != 2 {
+		t.Fatalf("switch to auto didn't query the terminal: %#v", cmd())
+	}
+
+	// Already auto: just the config watch, which has a reload waiting
+	_, cmd = updated.Update(configReloadMsg{})
+	notifyConfigChange()
+	if _, ok := cmd().(configReloadMsg); !ok {
+		t.Error("queried the terminal again without a switch")
+	}
+}
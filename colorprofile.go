@@ -0,0 +1,150 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// ui.color_profile values
+const (
+	colorProfileAuto      = "auto"
+	colorProfileTrueColor = "truecolor"
+	colorProfile256       = "256"
+	colorProfile16        = "16"
+	colorProfileMono      = "mono"
+)
+
+var colorProfiles = []string{colorProfileAuto, colorProfileTrueColor, colorProfile256, colorProfile16, colorProfileMono}
+
+// ansi16 are xterm's default RGB values for the 16 ANSI colors. Terminals
+// theme these, so they're only a best guess at what will show.
+var ansi16 = [16][3]uint8{
+	{0x00, 0x00, 0x00}, {0xcd, 0x00, 0x00}, {0x00, 0xcd, 0x00}, {0xcd, 0xcd, 0x00},
+	{0x00, 0x00, 0xee}, {0xcd, 0x00, 0xcd}, {0x00, 0xcd, 0xcd}, {0xe5, 0xe5, 0xe5},
+	{0x7f, 0x7f, 0x7f}, {0xff, 0x00, 0x00}, {0x00, 0xff, 0x00}, {0xff, 0xff, 0x00},
+	{0x5c, 0x5c, 0xff}, {0xff, 0x00, 0xff}, {0x00, 0xff, 0xff}, {0xff, 0xff, 0xff},
+}
+
+// ansi256RGB is the RGB value of a 256-color palette index
+func ansi256RGB(i int) [3]uint8 {
+	switch {
+	case i < 16:
+		return ansi16[i]
+	case i < 232:
+		// 6x6x6 color cube
+		levels := [6]uint8{0x00, 0x5f, 0x87, 0xaf, 0xd7, 0xff}
+		i -= 16
+		return [3]uint8{levels[i/36], levels[i/6%6], levels[i%6]}
+	default:
+		// 24-step grayscale ramp
+		v := uint8(8 + (i-232)*10)
+		return [3]uint8{v, v, v}
+	}
+}
+
+// Palette entries in OKLab, for nearest-color matching
+var (
+	oklab16  [16]oklab
+	oklab256 [256]oklab
+)
+
+func init() {
+	for i := range oklab256 {
+		oklab256[i] = rgbToOklab(ansi256RGB(i))
+	}
+	copy(oklab16[:], oklab256[:16])
+}
+
+// nearestColor is the index of the perceptually closest palette entry
+func nearestColor(rgb [3]uint8, entries []oklab) int {
+	c := rgbToOklab(rgb)
+	best, bestDist := 0, -1.0
+	for i, e := range entries {
+		d := (c.L-e.L)*(c.L-e.L) + (c.a-e.a)*(c.a-e.a) + (c.b-e.b)*(c.b-e.b)
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+// detectColorProfile works out what the terminal can show from the
+// environment: NO_COLOR, then COLORTERM, then TERM
+func detectColorProfile() string {
+	if os.Getenv("NO_COLOR") != "" {
+		return colorProfileMono
+	}
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return colorProfileTrueColor
+	}
+	term := strings.ToLower(os.Getenv("TERM"))
+	switch {
+	case term == "" || term == "dumb":
+		return colorProfileMono
+	case strings.Contains(term, "truecolor") || strings.Contains(term, "24bit") || strings.Contains(term, "direct"):
+		return colorProfileTrueColor
+	case strings.Contains(term, "256color"):
+		return colorProfile256
+	}
+	return colorProfile16
+}
+
+// detectedColorProfile is detectColorProfile's answer at startup
+var detectedColorProfile = colorProfileTrueColor
+
+// colorProfileFor resolves ui.color_profile, with auto meaning detected
+func colorProfileFor(cfg Config) string {
+	if cfg.UI.ColorProfile == "" || cfg.UI.ColorProfile == colorProfileAuto {
+		return detectedColorProfile
+	}
+	return cfg.UI.ColorProfile
+}
+
+// applyColorProfile makes lipgloss emit escape codes for the profile. Mono
+// stays on ANSI so bold, underline and reverse still show: its colors are
+// dropped by downsampleColor instead.
+func applyColorProfile(cfg Config) {
+	switch colorProfileFor(cfg) {
+	case colorProfileTrueColor:
+		lipgloss.SetColorProfile(termenv.TrueColor)
+	case colorProfile256:
+		lipgloss.SetColorProfile(termenv.ANSI256)
+	default:
+		lipgloss.SetColorProfile(termenv.ANSI)
+	}
+}
+
+// downsampleColor maps a color (hex or ANSI index) to the nearest one the
+// profile can show. Mono has no colors, so it returns "".
+func downsampleColor(c, profile string) string {
+	if c == "" {
+		return ""
+	}
+	var rgb [3]uint8
+	if hex, ok := parseHexColor(c); ok {
+		rgb = hex
+	} else if i, err := strconv.Atoi(c); err == nil && i >= 0 && i <= 255 {
+		rgb = ansi256RGB(i)
+		if profile == colorProfile256 || profile == colorProfileTrueColor || (profile == colorProfile16 && i < 16) {
+			return c // Already an index the terminal has
+		}
+	} else {
+		return c
+	}
+
+	switch profile {
+	case colorProfile256:
+		// The cube and grays only: 0-15 vary with the terminal's theme
+		return strconv.Itoa(16 + nearestColor(rgb, oklab256[16:]))
+	case colorProfile16:
+		return strconv.Itoa(nearestColor(rgb, oklab16[:]))
+	case colorProfileMono:
+		return ""
+	}
+	return c
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TestAnsi256RGB verifies the cube and grayscale ramp
+func TestAnsi256RGB(t *testing.T) {
+	assertEqual(t, ansi256RGB(16), [3]uint8{0, 0, 0}, "cube start")
+	assertEqual(t, ansi256RGB(196), [3]uint8{0xff, 0, 0}, "cube red")
+	assertEqual(t, ansi256RGB(231), [3]uint8{0xff, 0xff, 0xff}, "cube end")
+	assertEqual(t, ansi256RGB(232), [3]uint8{8, 8, 8}, "gray start")
+	assertEqual(t, ansi256RGB(255), [3]uint8{238, 238, 238}, "gray end")
+}
+
+// TestDownsampleColor verifies colors map to the nearest one a profile has
+func TestDownsampleColor(t *testing.T) {
+	tests := []struct {
+		color, profile, want string
+	}{
+		{"#ff0000", colorProfileTrueColor, "#ff0000"},
+		{"#ff0000", colorProfile256, "196"},
+		{"#fe0101", colorProfile256, "196"},
+		{"#808080", colorProfile256, "244"},
+		{"#ff0000", colorProfile16, "9"},
+		{"#0000c0", colorProfile16, "4"},
+		{"#e0e0e0", colorProfile16, "7"},
+		{"#fafafa", colorProfile16, "15"},
+		{"2", colorProfile16, "2"},
+		{"240", colorProfile256, "240"},
+		{"240", colorProfile16, "8"},
+		{"#ff0000", colorProfileMono, ""},
+		{"2", colorProfileMono, ""},
+		{"", colorProfile256, ""},
+	}
+
+	for _, tt := range tests {
+		if got := downsampleColor(tt.color, tt.profile); got != tt.want {
+			t.Errorf("downsampleColor(%q, %s) = %q, want %q", tt.color, tt.profile, got, tt.want)
+		}
+	}
+}
+
+// TestDetectColorProfile verifies NO_COLOR, COLORTERM and TERM are honored
+func TestDetectColorProfile(t *testing.T) {
+	tests := []struct {
+		noColor, colorTerm, term, want string
+	}{
+		{"1", "truecolor", "xterm-kitty", colorProfileMono},
+		{"", "truecolor", "xterm-kitty", colorProfileTrueColor},
+		{"", "24bit", "xterm", colorProfileTrueColor},
+		{"", "", "xterm-256color", colorProfile256},
+		{"", "", "xterm-direct", colorProfileTrueColor},
+		{"", "", "xterm", colorProfile16},
+		{"", "", "dumb", colorProfileMono},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("NO_COLOR", tt.noColor)
+		t.Setenv("COLORTERM", tt.colorTerm)
+		t.Setenv("TERM", tt.term)
+		if got := detectColorProfile(); got != tt.want {
+			t.Errorf("NO_COLOR=%q COLORTERM=%q TERM=%q: got %s, want %s", tt.noColor, tt.colorTerm, tt.term, got, tt.want)
+		}
+	}
+}
+
+// TestStylesColorProfile verifies theme colors are downsampled, and mono
+// drops colors for bold, underline and reverse
+func TestStylesColorProfile(t *testing.T) {
+	nord, err := loadTheme("nord")
+	assertNoError(t, err)
+	m := model{color: "#ff0000"}
+	cfg := Config{}
+	cfg.UI.Background = backgroundDark
+
+	cfg.UI.ColorProfile = colorProfile16
+	s := m.styles(nord, cfg)
+	assertEqual(t, s.error.GetForeground(), lipgloss.TerminalColor(lipgloss.Color(downsampleColor("#bf616a", colorProfile16))), "16-color error")
+
+	cfg.UI.ColorProfile = colorProfileMono
+	s = m.styles(nord, cfg)
+	assertEqual(t, s.title.GetForeground(), lipgloss.TerminalColor(lipgloss.NoColor{}), "mono title color")
+	if !s.title.GetBold() || !s.title.GetUnderline() {
+		t.Error("mono title should be bold and underlined")
+	}
+	if !s.error.GetReverse() {
+		t.Error("mono error should be reversed")
+	}
+}
+
+// TestMonoAttributes verifies mono output keeps the attributes theme.go sets
+// in place of colors, with no color codes
+func TestMonoAttributes(t *testing.T) {
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+	nord, err := loadTheme("nord")
+	assertNoError(t, err)
+	cfg := Config{}
+	cfg.UI.Background = backgroundDark
+	cfg.UI.ColorProfile = colorProfileMono
+	applyColorProfile(cfg)
+
+	s := model{color: "#ff0000"}.styles(nord, cfg)
+	title, errText := s.title.Render("Title"), s.error.Render("failed")
+	if !strings.HasPrefix(title, "\x1b[1;4") {
+		t.Errorf("mono title lost bold and underline: %q", title)
+	}
+	if !strings.HasPrefix(errText, "\x1b[1;7m") {
+		t.Errorf("mono error lost reverse: %q", errText)
+	}
+	for _, code := range regexp.MustCompile(`\x1b\[([0-9;]*)m`).FindAllStringSubmatch(title+errText, -1) {
+		for _, param := range strings.Split(code[1], ";") {
+			if !slices.Contains([]string{"0", "1", "4", "7"}, param) {
+				t.Errorf("mono output has a color: %q", code[0])
+			}
+		}
+	}
+}
@@ -0,0 +1,423 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// commandPrompt is the ":" prompt's state
+type commandPrompt struct {
+	active  bool
+	input   string
+	history []string // Commands run, oldest first
+	index   int      // Entry up/down is on (len(history) = a new line)
+	message string   // Completions for what's typed
+}
+
+// command is something the prompt can run
+type command struct {
+	name     string
+	usage    string // Arguments, for errors
+	run      func(m model, args []string) (model, tea.Cmd, error)
+	complete func(m model) []string // Candidates for the first argument (nil = none)
+}
+
+var commands = []command{
+	{"seek", "<m:ss | seconds | +/-seconds>", runSeek, nil},
+	{"volume", "<0-100 | +/-step>", runVolume, nil},
+	{"player", "<name | auto>", runPlayer, completePlayers},
+	{"theme", "<name>", runTheme, func(model) []string { return themeNames() }},
+	{"layout", "<name>", runLayout, func(model) []string { return layoutNames }},
+	{"set", "<setting> <value>", runSet, func(model) []string { return configKeys() }},
+	{"sleep", "<duration | off>", runSleep, func(model) []string { return []string{"off"} }},
+}
+
+// Every key action is a command too (":next", ":toggle_art")
+func init() {
+	for _, a := range keyActions {
+		if a.name == actionCommand {
+			continue
+		}
+		action := a.name
+		commands = append(commands, command{
+			name: action,
+			run: func(m model, args []string) (model, tea.Cmd, error) {
+				m, cmd := m.runAction(action)
+				return m, cmd, nil
+			},
+		})
+	}
+}
+
+func findCommand(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+// promptKey edits the prompt: enter runs it, esc closes it, tab completes
+// and up/down go through the history
+func (m model) promptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := &m.prompt
+	p.message = ""
+
+	switch msg.Type {
+	case tea.KeyEnter:
+		p.active = false
+		input := strings.TrimSpace(p.input)
+		if input == "" {
+			return m, nil
+		}
+		if len(p.history) == 0 || p.history[len(p.history)-1] != input {
+			p.history = append(p.history, input)
+		}
+		return m.runCommand(input)
+	case tea.KeyEsc, tea.KeyCtrlC:
+		p.active = false
+	case tea.KeyBackspace:
+		if p.input == "" {
+			// Backspace on the empty prompt closes it, as in vim
+			p.active = false
+			break
+		}
+		clusters := graphemes(p.input)
+		p.input = strings.Join(clusters[:len(clusters)-1], "")
+	case tea.KeyCtrlU:
+		p.input = ""
+	case tea.KeyTab:
+		var candidates []string
+		p.input, candidates = m.completeCommand(p.input)
+		if len(candidates) > 1 {
+			p.message = strings.Join(candidates, " ")
+		}
+	case tea.KeyUp:
+		if p.index > 0 {
+			p.index--
+			p.input = p.history[p.index]
+		}
+	case tea.KeyDown:
+		if p.index < len(p.history) {
+			p.index++
+			p.input = ""
+			if p.index < len(p.history) {
+				p.input = p.history[p.index]
+			}
+		}
+	case tea.KeySpace:
+		p.input += " "
+	case tea.KeyRunes:
+		if !msg.Alt {
+			p.input += string(msg.Runes)
+		}
+	}
+	return m, nil
+}
+
+// runCommand runs a command line, reporting errors (and usage) in a toast
+func (m model) runCommand(input string) (tea.Model, tea.Cmd) {
+	fields := strings.Fields(input)
+	c, ok := findCommand(fields[0])
+	if !ok {
+		m.warn(fmt.Sprintf("unknown command '%s'", fields[0]))
+		return m, nil
+	}
+
+	m, cmd, err := c.run(m, fields[1:])
+	switch {
+	case errors.Is(err, errUsage):
+		m.warn("usage: " + c.name + " " + c.usage)
+	case err != nil:
+		m.notifyError(err)
+	}
+	return m, cmd
+}
+
+// errUsage is a command's arguments not making sense
+var errUsage = errors.New("usage")
+
+// completeCommand completes the word being typed: the command name, or its
+// first argument. One match is filled in with a space after it; several
+// are filled in as far as they agree, and returned to show.
+func (m model) completeCommand(input string) (string, []string) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 || strings.HasSuffix(input, " ") {
+		fields = append(fields, "")
+	}
+
+	var options []string
+	switch len(fields) {
+	case 1:
+		for _, c := range commands {
+			options = append(options, c.name)
+		}
+	case 2:
+		if c, ok := findCommand(fields[0]); ok && c.complete != nil {
+			options = c.complete(m)
+		}
+	}
+
+	word := fields[len(fields)-1]
+	var matches []string
+	for _, option := range options {
+		if strings.HasPrefix(option, word) {
+			matches = append(matches, option)
+		}
+	}
+	if len(matches) == 0 {
+		return input, nil
+	}
+
+	completed := matches[0]
+	if len(matches) == 1 {
+		completed += " "
+	} else {
+		for _, match := range matches[1:] {
+			for !strings.HasPrefix(match, completed) {
+				completed = completed[:len(completed)-1]
+			}
+		}
+	}
+	fields[len(fields)-1] = completed
+	return strings.Join(fields, " "), matches
+}
+
+// promptLine is the open prompt, for the help line's place ("" when it's
+// closed). width bounds the completions.
+func (m model) promptLine(styles themeStyles, width int) string {
+	p := m.prompt
+	if !p.active {
+		return ""
+	}
+	line := styles.title.Render(":") + p.input + lipgloss.NewStyle().Reverse(true).Render(" ")
+	if p.message != "" {
+		line += "  " + styles.muted.Render(truncateText(p.message, width-cellWidth(p.input)-4))
+	}
+	return line
+}
+
+// parseClock parses "1:30", "1:02:03" or seconds ("90", "12.5")
+func parseClock(text string) (float64, error) {
+	parts := strings.Split(text, ":")
+	if len(parts) == 1 {
+		seconds, err := strconv.ParseFloat(text, 64)
+		if err != nil || seconds < 0 || math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+			return 0, errUsage
+		}
+		return seconds, nil
+	}
+	if len(parts) > 3 {
+		return 0, errUsage
+	}
+	var seconds float64
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || (i > 0 && n >= 60) {
+			return 0, errUsage
+		}
+		seconds = seconds*60 + float64(n)
+	}
+	return seconds, nil
+}
+
+// :seek 1:30, :seek +10
+func runSeek(m model, args []string) (model, tea.Cmd, error) {
+	if len(args) != 1 {
+		return m, nil, errUsage
+	}
+	if _, ok := m.mediaController.(Seeker); !ok {
+		return m, nil, errors.New("this player can't seek")
+	}
+
+	arg, sign := args[0], 0.0
+	if strings.HasPrefix(arg, "+") || strings.HasPrefix(arg, "-") {
+		arg, sign = arg[1:], 1
+		if args[0][0] == '-' {
+			sign = -1
+		}
+	}
+	seconds, err := parseClock(arg)
+	if err != nil {
+		return m, nil, err
+	}
+	if sign != 0 {
+		seconds = m.getCurrentPosition() + sign*seconds
+	}
+	seconds = max(seconds, 0)
+	if m.duration > 0 {
+		seconds = min(seconds, float64(m.duration))
+	}
+
+	updated, cmd := m.seek(seconds)
+	m = updated.(model)
+	m.notify("Seek to " + formatTime(int64(seconds)))
+	return m, cmd, nil
+}
+
+// :volume 40, :volume +5
+func runVolume(m model, args []string) (model, tea.Cmd, error) {
+	if len(args) != 1 {
+		return m, nil, errUsage
+	}
+	volume, ok := m.mediaController.(VolumeController)
+	if !ok {
+		return m, nil, errors.New("this player can't change the volume")
+	}
+
+	arg := strings.TrimSuffix(args[0], "%")
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < -100 || n > 100 {
+		return m, nil, errUsage
+	}
+	if strings.HasPrefix(arg, "+") || strings.HasPrefix(arg, "-") {
+		m.notify(fmt.Sprintf("Volume %+d%%", n))
+		return m, m.volumeCmd(float64(n) / 100), nil
+	}
+	m.notify(fmt.Sprintf("Volume %d%%", n))
+	return m, func() tea.Msg {
+		return controlMsg{err: volume.SetVolume(float64(n) / 100)}
+	}, nil
+}
+
+// :player spotify, :player auto
+func runPlayer(m model, args []string) (model, tea.Cmd, error) {
+	if len(args) != 1 {
+		return m, nil, errUsage
+	}
+	selector, ok := m.mediaController.(PlayerSelector)
+	if !ok {
+		return m, nil, errors.New("this platform can't choose a player")
+	}
+	name := args[0]
+	return m, tea.Sequence(
+		func() tea.Msg {
+			if err := selector.SetPlayer(name); err != nil {
+				return commandResultMsg{err: err}
+			}
+			return commandResultMsg{text: "Player: " + name}
+		},
+		m.fetchSongData(),
+	), nil
+}
+
+// completePlayers offers the running players
+func completePlayers(m model) []string {
+	selector, ok := m.mediaController.(PlayerSelector)
+	if !ok {
+		return nil
+	}
+	players, _ := selector.Players()
+	return append([]string{"auto"}, players...)
+}
+
+// :theme nord is :set ui.theme nord
+func runTheme(m model, args []string) (model, tea.Cmd, error) {
+	if len(args) != 1 {
+		return m, nil, errUsage
+	}
+	return runSet(m, []string{"ui.theme", args[0]})
+}
+
+// :layout compact is :set ui.layout compact
+func runLayout(m model, args []string) (model, tea.Cmd, error) {
+	if len(args) != 1 {
+		return m, nil, errUsage
+	}
+	return runSet(m, []string{"ui.layout", args[0]})
+}
+
+// :set artwork.vinyl_rpm 33.3 changes a setting until the config file next
+// changes. The result has to pass validateConfig: a value it rejects (or
+// that breaks another setting, like padding against max_width) is
+// reported and nothing changes.
+func runSet(m model, args []string) (model, tea.Cmd, error) {
+	if len(args) < 1 {
+		return m, nil, errUsage
+	}
+	key, value := args[0], strings.Join(args[1:], " ")
+
+	current := config.Get()
+	cfg := current
+	if err := setConfigValue(&cfg, key, value); err != nil {
+		return m, nil, err
+	}
+	if err := newConfigError(current, cfg); err != nil {
+		return m, nil, err
+	}
+
+	config.Set(cfg)
+	if cfg.UI.Theme != current.UI.Theme {
+		if t, err := loadTheme(cfg.UI.Theme); err == nil {
+			currentTheme.Set(t)
+		}
+	}
+	// Artwork, animation and palette changes are picked up like a reload
+	notifyConfigChange()
+
+	if slices.Contains(startupKeys, key) {
+		m.notify(key + " = " + value + " (on restart)")
+	} else {
+		m.notify(key + " = " + value)
+	}
+	return m, nil, nil
+}
+
+// newConfigError is the first validateConfig error cfg has that current
+// doesn't (nil if none)
+func newConfigError(current, cfg Config) error {
+	var before []string
+	for _, err := range validateConfig(&current) {
+		before = append(before, err.Error())
+	}
+	for _, err := range validateConfig(&cfg) {
+		if !slices.Contains(before, err.Error()) {
+			return err
+		}
+	}
+	return nil
+}
+
+// :sleep 30m pauses playback then (a bare number is minutes); :sleep off
+// cancels, and :sleep alone says how long is left
+func runSleep(m model, args []string) (model, tea.Cmd, error) {
+	switch {
+	case len(args) == 0 && m.sleepUntil.IsZero():
+		m.notify("No sleep timer")
+		return m, nil, nil
+	case len(args) == 0:
+		m.notify("Pausing in " + time.Until(m.sleepUntil).Round(time.Second).String())
+		return m, nil, nil
+	case len(args) > 1:
+		return m, nil, errUsage
+	case args[0] == "off":
+		m.sleepUntil = time.Time{}
+		m.notify("Sleep timer off")
+		return m, nil, nil
+	}
+
+	d, err := time.ParseDuration(args[0])
+	if minutes, convErr := strconv.Atoi(args[0]); convErr == nil {
+		d, err = time.Duration(minutes)*time.Minute, nil
+	}
+	if err != nil || d <= 0 {
+		return m, nil, errUsage
+	}
+
+	m.sleepSeq++
+	m.sleepUntil = time.Now().Add(d)
+	m.notify("Pausing in " + d.String())
+	seq := m.sleepSeq
+	return m, tea.Tick(d, func(time.Time) tea.Msg {
+		return sleepMsg{seq: seq}
+	}), nil
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// typeCommand opens the prompt, types line and presses enter
+func typeCommand(t *testing.T, m model, line string) (model, tea.Cmd) {
+	t.Helper()
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(":")})
+	if !updated.(model).prompt.active {
+		t.Fatal("':' didn't open the prompt")
+	}
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(line)})
+	updated, cmd := updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	return updated.(model), cmd
+}
+
+// drainConfigChange empties the reload notification :set sends
+func drainConfigChange() {
+	select {
+	case <-configChangeChan:
+	default:
+	}
+}
+
+// TestParseClock verifies :seek's times
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		text string
+		want float64
+		ok   bool
+	}{
+		{"90", 90, true},
+		{"12.5", 12.5, true},
+		{"1:30", 90, true},
+		{"1:02:03", 3723, true},
+		{"0:05", 5, true},
+		{"1:75", 0, false},
+		{"1:2:3:4", 0, false},
+		{"abc", 0, false},
+		{"-5", 0, false},
+		{"nan", 0, false},
+		{"NaN", 0, false},
+		{"inf", 0, false},
+		{"+Inf", 0, false},
+		{"1e309", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := parseClock(tt.text)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parseClock(%q) = %v, %v; want %v, ok %v", tt.text, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+// TestCompleteCommand verifies tab completes commands and their first
+// argument
+func TestCompleteCommand(t *testing.T) {
+	m := model{}
+	tests := []struct {
+		input, want string
+		matches     int
+	}{
+		{"sle", "sleep ", 1},
+		{"se", "se", 2}, // seek, set
+		{"set artwork.vinyl_r", "set artwork.vinyl_rpm ", 1},
+		{"set artwork.vinyl_", "set artwork.vinyl_", 9},
+		{"layout com", "layout compact ", 1},
+		{"layout c", "layout c", 2}, // card, compact
+		{"theme ", "theme ", len(themeNames())},
+		{"seek 1:", "seek 1:", 0},
+		{"zzz", "zzz", 0},
+	}
+	for _, tt := range tests {
+		got, matches := m.completeCommand(tt.input)
+		if got != tt.want || len(matches) != tt.matches {
+			t.Errorf("complete(%q) = %q, %d matches; want %q, %d", tt.input, got, len(matches), tt.want, tt.matches)
+		}
+	}
+
+	// Several matches are filled in as far as they agree
+	got, _ := m.completeCommand("set ui.time")
+	assertEqual(t, got, "set ui.time_display ", "one ui.time setting")
+	got, _ = m.completeCommand("set artwork.tra")
+	assertEqual(t, got, "set artwork.trans", "common prefix")
+}
+
+// TestSetConfigValue verifies settings parse as the config file would
+// spell them
+func TestSetConfigValue(t *testing.T) {
+	var cfg Config
+	assertNoError(t, setConfigValue(&cfg, "artwork.vinyl_rpm", "33.3"))
+	assertEqual(t, cfg.Artwork.VinylRPM, 33.3, "float")
+	assertNoError(t, setConfigValue(&cfg, "ui.max_width", "60"))
+	assertEqual(t, cfg.UI.MaxWidth, 60, "int")
+	assertNoError(t, setConfigValue(&cfg, "artwork.vinyl_tonearm", "on"))
+	assertEqual(t, cfg.Artwork.VinylTonearm, true, "bool")
+	assertNoError(t, setConfigValue(&cfg, "artwork.filters", "grayscale, vignette"))
+	assertEqual(t, strings.Join(cfg.Artwork.Filters, "|"), "grayscale|vignette", "list")
+	assertNoError(t, setConfigValue(&cfg, "ui.layout", "mini"))
+	assertEqual(t, cfg.UI.Layout, "mini", "string")
+
+	assertError(t, setConfigValue(&cfg, "ui.max_width", "wide"), "int from text")
+	assertError(t, setConfigValue(&cfg, "artwork.vinyl_rpm", "fast"), "float from text")
+	assertError(t, setConfigValue(&cfg, "artwork.enabled", "maybe"), "bool from text")
+	assertError(t, setConfigValue(&cfg, "ui.nope", "1"), "unknown setting")
+	assertError(t, setConfigValue(&cfg, "keys.quit", "x"), "map")
+	assertError(t, setConfigValue(&cfg, "ui.lines", "x"), "list of lines")
+
+	for _, key := range configKeys() {
+		if key == "ui.lines" || strings.HasPrefix(key, "keys") {
+			t.Errorf("configKeys lists %s", key)
+		}
+	}
+}
+
+// TestSetCommand verifies :set, :theme and :layout validate before
+// changing the config
+func TestSetCommand(t *testing.T) {
+	defer config.Set(config.Get())
+	defer drainConfigChange()
+	config.Set(layoutTestConfig(layoutCard))
+	m := model{width: 80, height: 30}
+
+	m, _ = typeCommand(t, m, "set artwork.vinyl_rpm 33.3")
+	assertEqual(t, config.Get().Artwork.VinylRPM, 33.3, "vinyl_rpm set")
+	assertEqual(t, m.toast.failed, false, "no error")
+	if !strings.Contains(m.View(), "artwork.vinyl_rpm = 33.3") {
+		t.Error("result not shown")
+	}
+
+	// Rejected by validateConfig: reported, and nothing changes
+	m, _ = typeCommand(t, m, "set artwork.vinyl_rpm 5000")
+	assertEqual(t, config.Get().Artwork.VinylRPM, 33.3, "vinyl_rpm kept")
+	assertEqual(t, m.toast.failed, true, "error reported")
+	if !strings.Contains(m.toast.text, "artwork.vinyl_rpm") {
+		t.Errorf("error doesn't name the setting: %q", m.toast.text)
+	}
+	m, _ = typeCommand(t, m, "set ui.max_width 16")
+	assertEqual(t, config.Get().UI.MaxWidth, 45, "max_width below padding kept")
+
+	m, _ = typeCommand(t, m, "layout compact")
+	assertEqual(t, config.Get().UI.Layout, layoutCompact, "layout")
+	m, _ = typeCommand(t, m, "theme nord")
+	assertEqual(t, config.Get().UI.Theme, "nord", "theme")
+	m, _ = typeCommand(t, m, "theme no-such-theme")
+	assertEqual(t, config.Get().UI.Theme, "nord", "unknown theme kept")
+
+	// The result goes once its time is up
+	m.expireToast(time.Now().Add(toastErrorDuration))
+	assertEqual(t, m.toast.text, "", "toast after it times out")
+}
+
+// TestCommandPrompt verifies editing, history and errors
+func TestCommandPrompt(t *testing.T) {
+	defer config.Set(config.Get())
+	config.Set(layoutTestConfig(layoutCard))
+	m := model{width: 80, height: 30}
+
+	m, _ = typeCommand(t, m, "dance")
+	assertEqual(t, m.toast.failed, true, "unknown command")
+	assertEqual(t, m.toast.text, "unknown command 'dance'", "unknown command message")
+	m, _ = typeCommand(t, m, "seek")
+	assertEqual(t, strings.HasPrefix(m.toast.text, "usage: seek"), true, "usage")
+
+	// Keys go to the prompt, not the keymap
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(":")})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	assertEqual(t, updated.(model).quitting, false, "q typed")
+	assertEqual(t, updated.(model).prompt.input, "q", "input")
+	if !strings.Contains(updated.(model).View(), ":q") {
+		t.Error("prompt not shown")
+	}
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	assertEqual(t, updated.(model).prompt.input, "", "backspace")
+
+	// Up and down walk the history, newest first
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyUp})
+	assertEqual(t, updated.(model).prompt.input, "seek", "up")
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyUp})
+	assertEqual(t, updated.(model).prompt.input, "dance", "up twice")
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assertEqual(t, updated.(model).prompt.input, "", "back down")
+
+	// Tab with several matches lists them
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("se")})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyTab})
+	assertEqual(t, updated.(model).prompt.message, "seek set", "candidates")
+
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assertEqual(t, updated.(model).prompt.active, false, "esc closes")
+
+	// Key actions are commands too
+	m, _ = typeCommand(t, updated.(model), "help")
+	assertEqual(t, m.showHelp, true, ":help")
+}
+
+// TestPlaybackCommands verifies :seek, :volume and :sleep reach the
+// controller
+func TestPlaybackCommands(t *testing.T) {
+	defer config.Set(config.Get())
+	m, controller := mouseTestModel(t, layoutCard)
+
+	m, cmd := typeCommand(t, m, "seek 1:30")
+	if cmd == nil || m.lastPosition != 90 {
+		t.Errorf("seek 1:30 went to %.1f", m.lastPosition)
+	}
+	m, _ = typeCommand(t, m, "seek +1:00")
+	if m.lastPosition < 150 || m.lastPosition > 151 {
+		t.Errorf("seek +1:00 went to %.1f", m.lastPosition)
+	}
+	m, _ = typeCommand(t, m, "seek 10:00")
+	assertEqual(t, m.lastPosition, 200.0, "seek past the end")
+
+	m, cmd = typeCommand(t, m, "volume 40")
+	cmd()
+	assertEqual(t, len(controller.levels), 1, "volume levels")
+	assertEqual(t, controller.levels[0], 0.4, "volume 40")
+	m, cmd = typeCommand(t, m, "volume -10")
+	cmd()
+	assertEqual(t, controller.volumes[0], -0.1, "volume -10")
+	m, _ = typeCommand(t, m, "volume 140")
+	assertEqual(t, m.toast.failed, true, "volume out of range")
+
+	// The timer pauses only if it's still the latest one, and pauses
+	// outright rather than trusting a stale isPlaying
+	m.isPlaying = false
+	m, cmd = typeCommand(t, m, "sleep 30m")
+	if cmd == nil || time.Until(m.sleepUntil) < 29*time.Minute {
+		t.Fatal("sleep 30m didn't start a timer")
+	}
+	updated, cmd := m.Update(sleepMsg{seq: m.sleepSeq - 1})
+	assertEqual(t, cmd == nil, true, "stale timer")
+	updated, cmd = updated.Update(sleepMsg{seq: m.sleepSeq})
+	assertEqual(t, updated.(model).sleepUntil.IsZero(), true, "timer done")
+	if cmd == nil {
+		t.Fatal("timer didn't pause")
+	}
+	// controlCmd is a sequence: the control, then a fetch
+	reflect.ValueOf(cmd()).Index(0).Interface().(tea.Cmd)()
+	assertEqual(t, strings.Join(controller.controls, ","), "pause", "control sent")
+
+	m, _ = typeCommand(t, m, "sleep off")
+	assertEqual(t, m.sleepUntil.IsZero(), true, "sleep off")
+
+	// Controllers without seek or volume say so
+	m.mediaController = basicController{}
+	m, _ = typeCommand(t, m, "seek 0:10")
+	assertEqual(t, m.toast.text, "this player can't seek", "no Seeker")
+	m, _ = typeCommand(t, m, "player spotify")
+	assertEqual(t, m.toast.failed, true, "no PlayerSelector")
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -16,23 +18,49 @@ import (
 // Config holds all application configuration
 type Config struct {
 	UI struct {
-		Color     string `mapstructure:"color"`
-		ColorMode string `mapstructure:"color_mode"`
-		MaxWidth  int    `mapstructure:"max_width"`
+		Color         string       `mapstructure:"color"`
+		ColorMode     string       `mapstructure:"color_mode"`
+		MaxWidth      int          `mapstructure:"max_width"`
+		Palette       string       `mapstructure:"palette"`        // Auto color strategy: "vibrant", "muted", "pastel" or "complementary"
+		Background    string       `mapstructure:"background"`     // Terminal background: "auto" (ask the terminal), "dark" or "light"
+		Theme         string       `mapstructure:"theme"`          // Built-in preset or a file in ~/.config/goplaying/themes
+		ColorProfile  string       `mapstructure:"color_profile"`  // Colors the terminal shows: "auto", "truecolor", "256", "16" or "mono"
+		Layout        string       `mapstructure:"layout"`         // "card", "vertical", "compact", "fullscreen", "mini" or "auto" (by terminal size)
+		Inline        bool         `mapstructure:"inline"`         // Render in the shell's scrollback instead of the alternate screen (read at startup)
+		InlineHeight  int          `mapstructure:"inline_height"`  // Rows the UI may take when inline
+		Lines         []lineConfig `mapstructure:"lines"`          // Text lines as templates (empty = title, artist, album, status)
+		Icons         string       `mapstructure:"icons"`          // Glyphs: "nerd" (Nerd Font), "emoji", "ascii" or "none"
+		ProgressStyle string       `mapstructure:"progress_style"` // "block", "smooth", "braille", "thin", "dotted" or "gradient"
+		TimeDisplay   string       `mapstructure:"time_display"`   // Beside the bar: "total", "elapsed" or "remaining" (t cycles)
+		Mouse         bool         `mapstructure:"mouse"`          // Click to seek and control playback, scroll for volume (read at startup)
 	} `mapstructure:"ui"`
 	Artwork struct {
-		Enabled      bool    `mapstructure:"enabled"`
-		Padding      int     `mapstructure:"padding"`
-		WidthPixels  int     `mapstructure:"width_pixels"`
-		WidthColumns int     `mapstructure:"width_columns"`
-		VinylMode    bool    `mapstructure:"vinyl_mode"`
-		VinylRPM     float64 `mapstructure:"vinyl_rpm"`
-		VinylFrames  int     `mapstructure:"vinyl_frames"` // Number of pre-rendered frames (45 or 90)
+		Enabled        bool     `mapstructure:"enabled"`
+		Padding        int      `mapstructure:"padding"`
+		WidthPixels    int      `mapstructure:"width_pixels"`
+		WidthColumns   int      `mapstructure:"width_columns"`
+		Animation      string   `mapstructure:"animation"` // Artwork animation: "none", "vinyl", "cassette", "cd", "kenburns" or "pulse"
+		Filters        []string `mapstructure:"filters"`   // Effects applied in order, e.g. [grayscale, vignette]
+		VinylMode      bool     `mapstructure:"vinyl_mode"`
+		VinylRPM       float64  `mapstructure:"vinyl_rpm"`
+		VinylFrames    int      `mapstructure:"vinyl_frames"`    // Maximum pre-rendered frames per revolution
+		VinylMemoryMB  int      `mapstructure:"vinyl_memory_mb"` // Memory cap for pre-rendered frames (may lower the frame count)
+		Transmission   string   `mapstructure:"transmission"`    // How image data reaches the terminal ("direct" or "file")
+		VinylPlayback  string   `mapstructure:"vinyl_playback"`  // Who spins the record: "auto", "terminal" (Kitty animation) or "frames"
+		VinylLabel     bool     `mapstructure:"vinyl_label"`     // Draw a ring around the center label
+		VinylSpindle   bool     `mapstructure:"vinyl_spindle"`   // Punch a spindle hole in the middle
+		VinylGrooves   bool     `mapstructure:"vinyl_grooves"`   // Shade concentric grooves outside the label
+		VinylTonearm   bool     `mapstructure:"vinyl_tonearm"`   // Overlay a tonearm that tracks playback progress
+		AnimatedCovers bool     `mapstructure:"animated_covers"` // Play animated GIF/WebP covers (when animation is none)
+		Transition     string   `mapstructure:"transition"`      // Cover change effect: "none", "crossfade" or "slide"
+		TransitionMs   int      `mapstructure:"transition_ms"`   // Transition (and accent color fade) duration
 	} `mapstructure:"artwork"`
 	Text struct {
-		MaxLengthWithArt int `mapstructure:"max_length_with_art"`
-		MaxLengthNoArt   int `mapstructure:"max_length_no_art"`
+		Overflow      string `mapstructure:"overflow"`        // Text too wide for its line: "marquee", "bounce", "ellipsis" or "wrap"
+		ScrollSpeed   int    `mapstructure:"scroll_speed"`    // Characters per second
+		ScrollPauseMs int    `mapstructure:"scroll_pause_ms"` // Pause before each pass (and at each end for bounce)
 	} `mapstructure:"text"`
+	Keys   map[string][]string `mapstructure:"keys"` // Action → keys, over the defaults (see keyActions)
 	Timing struct {
 		UIRefreshMs int `mapstructure:"ui_refresh_ms"`
 		DataFetchMs int `mapstructure:"data_fetch_ms"`
@@ -124,6 +152,76 @@ func validateConfig(cfg *Config) []error {
 		})
 	}
 
+	if !slices.Contains(paletteStrategies, cfg.UI.Palette) {
+		errors = append(errors, configError{
+			field:   "ui.palette",
+			message: fmt.Sprintf("must be one of %s (got '%s')", strings.Join(paletteStrategies, ", "), cfg.UI.Palette),
+		})
+	}
+
+	if cfg.UI.Background != backgroundAuto && cfg.UI.Background != backgroundDark && cfg.UI.Background != backgroundLight {
+		errors = append(errors, configError{
+			field:   "ui.background",
+			message: fmt.Sprintf("must be 'auto', 'dark' or 'light' (got '%s')", cfg.UI.Background),
+		})
+	}
+
+	if !slices.Contains(layoutNames, cfg.UI.Layout) {
+		errors = append(errors, configError{
+			field:   "ui.layout",
+			message: fmt.Sprintf("must be one of %s (got '%s')", strings.Join(layoutNames, ", "), cfg.UI.Layout),
+		})
+	}
+
+	if cfg.UI.InlineHeight < 1 || cfg.UI.InlineHeight > 500 {
+		errors = append(errors, configError{
+			field:   "ui.inline_height",
+			message: fmt.Sprintf("must be >= 1 and <= 500 (got %d)", cfg.UI.InlineHeight),
+		})
+	}
+
+	if _, err := compileLines(cfg.UI.Lines); err != nil {
+		errors = append(errors, configError{
+			field:   "ui.lines",
+			message: err.Error(),
+		})
+	}
+
+	if !slices.Contains(colorProfiles, cfg.UI.ColorProfile) {
+		errors = append(errors, configError{
+			field:   "ui.color_profile",
+			message: fmt.Sprintf("must be one of %s (got '%s')", strings.Join(colorProfiles, ", "), cfg.UI.ColorProfile),
+		})
+	}
+
+	if !slices.Contains(progressStyles, cfg.UI.ProgressStyle) {
+		errors = append(errors, configError{
+			field:   "ui.progress_style",
+			message: fmt.Sprintf("must be one of %s (got '%s')", strings.Join(progressStyles, ", "), cfg.UI.ProgressStyle),
+		})
+	}
+
+	if !slices.Contains(timeDisplays, cfg.UI.TimeDisplay) {
+		errors = append(errors, configError{
+			field:   "ui.time_display",
+			message: fmt.Sprintf("must be one of %s (got '%s')", strings.Join(timeDisplays, ", "), cfg.UI.TimeDisplay),
+		})
+	}
+
+	if !slices.Contains(iconSetNames, cfg.UI.Icons) {
+		errors = append(errors, configError{
+			field:   "ui.icons",
+			message: fmt.Sprintf("must be one of %s (got '%s')", strings.Join(iconSetNames, ", "), cfg.UI.Icons),
+		})
+	}
+
+	if _, err := loadTheme(cfg.UI.Theme); err != nil {
+		errors = append(errors, configError{
+			field:   "ui.theme",
+			message: err.Error(),
+		})
+	}
+
 	if !isValidColor(cfg.UI.Color) {
 		errors = append(errors, configError{
 			field:   "ui.color",
@@ -160,6 +258,37 @@ func validateConfig(cfg *Config) []error {
 		})
 	}
 
+	if _, ok := animations[cfg.Artwork.Animation]; !ok && cfg.Artwork.Animation != animationNone {
+		errors = append(errors, configError{
+			field:   "artwork.animation",
+			message: fmt.Sprintf("must be one of %s (got '%s')", strings.Join(animationOrder, ", "), cfg.Artwork.Animation),
+		})
+	}
+
+	for _, name := range cfg.Artwork.Filters {
+		if _, ok := artworkFilters[name]; !ok {
+			errors = append(errors, configError{
+				field:   "artwork.filters",
+				message: fmt.Sprintf("unknown filter '%s' (must be %s)", name, strings.Join(artworkFilterNames, ", ")),
+			})
+			break
+		}
+	}
+
+	if cfg.Artwork.Transition != transitionNone && cfg.Artwork.Transition != "crossfade" && cfg.Artwork.Transition != "slide" {
+		errors = append(errors, configError{
+			field:   "artwork.transition",
+			message: fmt.Sprintf("must be 'none', 'crossfade' or 'slide' (got '%s')", cfg.Artwork.Transition),
+		})
+	}
+
+	if cfg.Artwork.TransitionMs < 50 || cfg.Artwork.TransitionMs > 2000 {
+		errors = append(errors, configError{
+			field:   "artwork.transition_ms",
+			message: fmt.Sprintf("must be >= 50 and <= 2000 (got %d)", cfg.Artwork.TransitionMs),
+		})
+	}
+
 	if cfg.Artwork.VinylRPM <= 0 || cfg.Artwork.VinylRPM > 1000 {
 		errors = append(errors, configError{
 			field:   "artwork.vinyl_rpm",
@@ -167,25 +296,61 @@ func validateConfig(cfg *Config) []error {
 		})
 	}
 
-	if cfg.Artwork.VinylFrames != 45 && cfg.Artwork.VinylFrames != 90 {
+	if cfg.Artwork.VinylFrames < animMinCoarseFrames || cfg.Artwork.VinylFrames > 360 {
 		errors = append(errors, configError{
 			field:   "artwork.vinyl_frames",
-			message: fmt.Sprintf("must be 45 or 90 (got %d)", cfg.Artwork.VinylFrames),
+			message: fmt.Sprintf("must be >= %d and <= 360 (got %d)", animMinCoarseFrames, cfg.Artwork.VinylFrames),
+		})
+	}
+
+	if cfg.Artwork.VinylMemoryMB < 1 || cfg.Artwork.VinylMemoryMB > 1024 {
+		errors = append(errors, configError{
+			field:   "artwork.vinyl_memory_mb",
+			message: fmt.Sprintf("must be >= 1 and <= 1024 (got %d)", cfg.Artwork.VinylMemoryMB),
+		})
+	}
+
+	if cfg.Artwork.Transmission != kittyTransmitDirect && cfg.Artwork.Transmission != kittyTransmitFile {
+		errors = append(errors, configError{
+			field:   "artwork.transmission",
+			message: fmt.Sprintf("must be 'direct' or 'file' (got '%s')", cfg.Artwork.Transmission),
+		})
+	}
+
+	if cfg.Artwork.VinylPlayback != "auto" && cfg.Artwork.VinylPlayback != "terminal" && cfg.Artwork.VinylPlayback != "frames" {
+		errors = append(errors, configError{
+			field:   "artwork.vinyl_playback",
+			message: fmt.Sprintf("must be 'auto', 'terminal' or 'frames' (got '%s')", cfg.Artwork.VinylPlayback),
 		})
 	}
 
 	// Text validation
-	if cfg.Text.MaxLengthWithArt <= 0 || cfg.Text.MaxLengthWithArt > 200 {
+	if !slices.Contains(overflowModes, cfg.Text.Overflow) {
+		errors = append(errors, configError{
+			field:   "text.overflow",
+			message: fmt.Sprintf("must be one of %s (got '%s')", strings.Join(overflowModes, ", "), cfg.Text.Overflow),
+		})
+	}
+
+	if cfg.Text.ScrollSpeed < 1 || cfg.Text.ScrollSpeed > 100 {
+		errors = append(errors, configError{
+			field:   "text.scroll_speed",
+			message: fmt.Sprintf("must be >= 1 and <= 100 (got %d)", cfg.Text.ScrollSpeed),
+		})
+	}
+
+	if cfg.Text.ScrollPauseMs < 0 || cfg.Text.ScrollPauseMs > 60000 {
 		errors = append(errors, configError{
-			field:   "text.max_length_with_art",
-			message: fmt.Sprintf("must be > 0 and <= 200 (got %d)", cfg.Text.MaxLengthWithArt),
+			field:   "text.scroll_pause_ms",
+			message: fmt.Sprintf("must be >= 0 and <= 60000 (got %d)", cfg.Text.ScrollPauseMs),
 		})
 	}
 
-	if cfg.Text.MaxLengthNoArt <= 0 || cfg.Text.MaxLengthNoArt > 200 {
+	// Keys validation
+	if _, err := buildKeymap(cfg.Keys); err != nil {
 		errors = append(errors, configError{
-			field:   "text.max_length_no_art",
-			message: fmt.Sprintf("must be > 0 and <= 200 (got %d)", cfg.Text.MaxLengthNoArt),
+			field:   "keys",
+			message: err.Error(),
 		})
 	}
 
@@ -222,20 +387,58 @@ func applyDefaultsForInvalidFields(cfg *Config, errors []error) {
 			cfg.UI.ColorMode = "auto"
 		case "ui.color":
 			cfg.UI.Color = "2"
+		case "ui.palette":
+			cfg.UI.Palette = "vibrant"
+		case "ui.background":
+			cfg.UI.Background = backgroundAuto
+		case "ui.theme":
+			cfg.UI.Theme = defaultThemeName
+		case "ui.layout":
+			cfg.UI.Layout = layoutCard
+		case "ui.lines":
+			cfg.UI.Lines = nil
+		case "ui.icons":
+			cfg.UI.Icons = "nerd"
+		case "ui.progress_style":
+			cfg.UI.ProgressStyle = progressBlock
+		case "ui.time_display":
+			cfg.UI.TimeDisplay = timeTotal
+		case "ui.inline_height":
+			cfg.UI.InlineHeight = 16
+		case "ui.color_profile":
+			cfg.UI.ColorProfile = colorProfileAuto
 		case "artwork.padding":
 			cfg.Artwork.Padding = 16
 		case "artwork.width_pixels":
 			cfg.Artwork.WidthPixels = 300
 		case "artwork.width_columns":
 			cfg.Artwork.WidthColumns = 14
+		case "artwork.animation":
+			cfg.Artwork.Animation = animationNone
+		case "artwork.filters":
+			cfg.Artwork.Filters = nil
+		case "artwork.transition":
+			cfg.Artwork.Transition = transitionNone
+		case "artwork.transition_ms":
+			cfg.Artwork.TransitionMs = 400
 		case "artwork.vinyl_rpm":
 			cfg.Artwork.VinylRPM = 10.0
 		case "artwork.vinyl_frames":
 			cfg.Artwork.VinylFrames = 90
-		case "text.max_length_with_art":
-			cfg.Text.MaxLengthWithArt = 22
-		case "text.max_length_no_art":
-			cfg.Text.MaxLengthNoArt = 36
+		case "artwork.vinyl_memory_mb":
+			cfg.Artwork.VinylMemoryMB = 64
+		case "artwork.transmission":
+			cfg.Artwork.Transmission = kittyTransmitDirect
+		case "artwork.vinyl_playback":
+			cfg.Artwork.VinylPlayback = "auto"
+		case "text.overflow":
+			cfg.Text.Overflow = overflowMarquee
+		case "text.scroll_speed":
+			cfg.Text.ScrollSpeed = 3
+		case "text.scroll_pause_ms":
+			cfg.Text.ScrollPauseMs = 3000
+		case "keys":
+			cfg.Keys = nil
 		case "timing.ui_refresh_ms":
 			cfg.Timing.UIRefreshMs = 100
 		case "timing.data_fetch_ms":
@@ -244,6 +447,99 @@ func applyDefaultsForInvalidFields(cfg *Config, errors []error) {
 	}
 }
 
+// startupKeys are settings only read at startup
+var startupKeys = []string{"ui.inline", "ui.mouse"}
+
+// settable reports whether setConfigValue can parse a value of type t
+func settable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Int, reflect.Float64, reflect.Bool:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+// configKeys lists the settings setConfigValue can change, named as in the
+// config file ("ui.layout"): all but ui.lines and keys
+func configKeys() []string {
+	var keys []string
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		section := t.Field(i)
+		if section.Type.Kind() != reflect.Struct {
+			continue
+		}
+		for j := 0; j < section.Type.NumField(); j++ {
+			if field := section.Type.Field(j); settable(field.Type) {
+				keys = append(keys, section.Tag.Get("mapstructure")+"."+field.Tag.Get("mapstructure"))
+			}
+		}
+	}
+	return keys
+}
+
+// fieldByTag is v's field with the given mapstructure name
+func fieldByTag(v reflect.Value, name string) (reflect.Value, bool) {
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).Tag.Get("mapstructure") == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// setConfigValue sets one of configKeys from text, as the config file
+// would spell it. Lists are comma-separated. It doesn't validate the
+// result; see validateConfig.
+func setConfigValue(cfg *Config, key, text string) error {
+	sectionName, fieldName, _ := strings.Cut(key, ".")
+	section, ok := fieldByTag(reflect.ValueOf(cfg).Elem(), sectionName)
+	var field reflect.Value
+	if ok && section.Kind() == reflect.Struct {
+		field, ok = fieldByTag(section, fieldName)
+	}
+	if !ok || section.Kind() != reflect.Struct || !settable(field.Type()) {
+		return configError{field: key, message: "unknown setting"}
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(text)
+	case reflect.Int:
+		n, err := strconv.Atoi(text)
+		if err != nil {
+			return configError{field: key, message: fmt.Sprintf("must be a whole number (got '%s')", text)}
+		}
+		field.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return configError{field: key, message: fmt.Sprintf("must be a number (got '%s')", text)}
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		switch strings.ToLower(text) {
+		case "true", "on", "yes":
+			field.SetBool(true)
+		case "false", "off", "no":
+			field.SetBool(false)
+		default:
+			return configError{field: key, message: fmt.Sprintf("must be true or false (got '%s')", text)}
+		}
+	case reflect.Slice:
+		items := []string{}
+		for _, item := range strings.Split(text, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	}
+	return nil
+}
+
 // printConfigWarnings prints validation errors to stderr with helpful formatting
 func printConfigWarnings(errors []error) {
 	if len(errors) == 0 {
@@ -259,22 +555,56 @@ func printConfigWarnings(errors []error) {
 }
 
 // Config file changed notification
-type configReloadMsg struct{}
+type configReloadMsg struct {
+	rejected []error // Why the file wasn't applied: validation errors, or its parse error
+}
 
-var configChangeChan = make(chan struct{}, 1)
+var configChangeChan = make(chan configReloadMsg, 1)
 
 // Watch for config file changes
 func watchConfigCmd() tea.Cmd {
 	return func() tea.Msg {
-		<-configChangeChan
-		return configReloadMsg{}
+		return <-configChangeChan
 	}
 }
 
+// notifyConfigChange wakes watchConfigCmd
+func notifyConfigChange() {
+	select {
+	case configChangeChan <- configReloadMsg{}:
+	default:
+		// Channel full, skip notification
+	}
+}
+
+// notifyConfigRejected tells the app a changed config file was invalid
+// and the old config is still in use
+func notifyConfigRejected(errs []error) {
+	select {
+	case configChangeChan <- configReloadMsg{rejected: errs}:
+	default:
+		// Channel full, skip notification
+	}
+}
+
+// configDir is ~/.config/goplaying, following XDG ("" if unknown)
+func configDir() string {
+	// Check XDG_CONFIG_HOME first, fallback to ~/.config
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		configHome = filepath.Join(homeDir, ".config")
+	}
+	return filepath.Join(configHome, "goplaying")
+}
+
 // lastFileCfg tracks the config as last loaded from the file (before runtime
 // keybind toggles). Used on live reload to distinguish "user edited this key
 // in the file" (file wins) from "unrelated edit" (runtime toggle preserved).
-// Only touched from initConfig and the fsnotify callback.
+// Only touched from initConfig and reloadConfig.
 var lastFileCfg Config
 
 func initConfig(explicitFlags map[string]bool) {
@@ -282,15 +612,39 @@ func initConfig(explicitFlags map[string]bool) {
 	viper.SetDefault("ui.color", "2")
 	viper.SetDefault("ui.color_mode", "auto")
 	viper.SetDefault("ui.max_width", 45)
+	viper.SetDefault("ui.palette", "vibrant")
+	viper.SetDefault("ui.background", backgroundAuto)
+	viper.SetDefault("ui.theme", defaultThemeName)
+	viper.SetDefault("ui.color_profile", colorProfileAuto)
+	viper.SetDefault("ui.layout", layoutCard)
+	viper.SetDefault("ui.inline", false)
+	viper.SetDefault("ui.inline_height", 16)
+	viper.SetDefault("ui.icons", "nerd")
+	viper.SetDefault("ui.progress_style", progressBlock)
+	viper.SetDefault("ui.time_display", timeTotal)
+	viper.SetDefault("ui.mouse", true)
 	viper.SetDefault("artwork.enabled", true)
 	viper.SetDefault("artwork.padding", 16)
 	viper.SetDefault("artwork.width_pixels", 300)
 	viper.SetDefault("artwork.width_columns", 14)
+	viper.SetDefault("artwork.animation", animationNone)
+	viper.SetDefault("artwork.filters", []string{})
 	viper.SetDefault("artwork.vinyl_mode", false) // Disabled by default - see config.example.yaml
 	viper.SetDefault("artwork.vinyl_rpm", 10.0)   // Slow, dramatic spin when enabled
-	viper.SetDefault("artwork.vinyl_frames", 90)  // Ultra-smooth (use 45 for half memory)
-	viper.SetDefault("text.max_length_with_art", 22)
-	viper.SetDefault("text.max_length_no_art", 36)
+	viper.SetDefault("artwork.vinyl_frames", 90)  // Ultra-smooth, if it fits in vinyl_memory_mb
+	viper.SetDefault("artwork.vinyl_memory_mb", 64)
+	viper.SetDefault("artwork.transmission", "direct")
+	viper.SetDefault("artwork.vinyl_playback", "auto") // Terminal-side animation when supported
+	viper.SetDefault("artwork.vinyl_label", false)
+	viper.SetDefault("artwork.vinyl_spindle", false)
+	viper.SetDefault("artwork.vinyl_grooves", false)
+	viper.SetDefault("artwork.vinyl_tonearm", false)
+	viper.SetDefault("artwork.animated_covers", true)
+	viper.SetDefault("artwork.transition", transitionNone)
+	viper.SetDefault("artwork.transition_ms", 400)
+	viper.SetDefault("text.overflow", overflowMarquee)
+	viper.SetDefault("text.scroll_speed", 3)
+	viper.SetDefault("text.scroll_pause_ms", 3000)
 	viper.SetDefault("timing.ui_refresh_ms", 100)
 	viper.SetDefault("timing.data_fetch_ms", 1000)
 
@@ -298,17 +652,8 @@ func initConfig(explicitFlags map[string]bool) {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
 
-	// Check XDG_CONFIG_HOME first, fallback to ~/.config
-	configHome := os.Getenv("XDG_CONFIG_HOME")
-	if configHome == "" {
-		homeDir, err := os.UserHomeDir()
-		if err == nil {
-			configHome = filepath.Join(homeDir, ".config")
-		}
-	}
-
-	if configHome != "" {
-		viper.AddConfigPath(filepath.Join(configHome, "goplaying"))
+	if dir := configDir(); dir != "" {
+		viper.AddConfigPath(dir)
 	}
 
 	// Environment variable support with GOPLAYING_ prefix
@@ -331,6 +676,12 @@ func initConfig(explicitFlags map[string]bool) {
 	if explicitFlags["no-artwork"] {
 		viper.Set("artwork.enabled", false)
 	}
+	if explicitFlags["mini"] && miniFlag {
+		viper.Set("ui.layout", layoutMini)
+	}
+	if explicitFlags["inline"] && inlineFlag {
+		viper.Set("ui.inline", true)
+	}
 
 	// Unmarshal into config struct
 	var cfg Config
@@ -346,40 +697,65 @@ func initConfig(explicitFlags map[string]bool) {
 
 	config.Set(cfg)
 	lastFileCfg = cfg
+	// Validated above, so this only fails for the (built-in) default
+	if t, err := loadTheme(cfg.UI.Theme); err == nil {
+		currentTheme.Set(t)
+	}
 
 	// Watch for config file changes and live reload
 	viper.OnConfigChange(func(e fsnotify.Event) {
-		var newCfg Config
-		if err := viper.Unmarshal(&newCfg); err == nil {
-			// Validate the new config
-			if validationErrors := validateConfig(&newCfg); len(validationErrors) > 0 {
-				// Invalid config - silently keep old config
-				// Don't print to stderr during TUI operation as it corrupts the display
-				return
-			}
-
-			// Preserve runtime keybind toggles ('a'/'v') across reloads: if the
-			// file value for a togglable key didn't change, keep the current
-			// in-memory value; if the user actually edited it, the file wins.
-			fileCfg := newCfg
-			cur := config.Get()
-			if newCfg.Artwork.Enabled == lastFileCfg.Artwork.Enabled {
-				newCfg.Artwork.Enabled = cur.Artwork.Enabled
-			}
-			if newCfg.Artwork.VinylMode == lastFileCfg.Artwork.VinylMode {
-				newCfg.Artwork.VinylMode = cur.Artwork.VinylMode
-			}
-			lastFileCfg = fileCfg
-
-			// Valid config - apply it
-			config.Set(newCfg)
-			// Config reloaded successfully, notify the app
-			select {
-			case configChangeChan <- struct{}{}:
-			default:
-				// Channel full, skip notification
-			}
-		}
+		reloadConfig()
 	})
 	viper.WatchConfig()
+	watchThemes()
+}
+
+// reloadConfig applies the config file after it changes. viper calls it even
+// when the file no longer parses (keeping the old values), so the file is
+// read again here: one that doesn't parse, unmarshal or validate is reported
+// in a toast and the old config stays.
+func reloadConfig() {
+	if err := viper.ReadInConfig(); err != nil {
+		notifyConfigRejected([]error{err})
+		return
+	}
+	var newCfg Config
+	if err := viper.Unmarshal(&newCfg); err != nil {
+		notifyConfigRejected([]error{err})
+		return
+	}
+
+	// Validate the new config
+	if validationErrors := validateConfig(&newCfg); len(validationErrors) > 0 {
+		// Invalid config - keep the old config, and say so in a toast
+		// (printing to stderr during TUI operation corrupts the display)
+		notifyConfigRejected(validationErrors)
+		return
+	}
+
+	// Preserve runtime keybind toggles ('a'/'v'/'t') across reloads: if the
+	// file value for a togglable key didn't change, keep the current
+	// in-memory value; if the user actually edited it, the file wins.
+	fileCfg := newCfg
+	cur := config.Get()
+	if newCfg.Artwork.Enabled == lastFileCfg.Artwork.Enabled {
+		newCfg.Artwork.Enabled = cur.Artwork.Enabled
+	}
+	if newCfg.Artwork.Animation == lastFileCfg.Artwork.Animation &&
+		newCfg.Artwork.VinylMode == lastFileCfg.Artwork.VinylMode {
+		newCfg.Artwork.Animation = cur.Artwork.Animation
+		newCfg.Artwork.VinylMode = cur.Artwork.VinylMode
+	}
+	if newCfg.UI.TimeDisplay == lastFileCfg.UI.TimeDisplay {
+		newCfg.UI.TimeDisplay = cur.UI.TimeDisplay
+	}
+	lastFileCfg = fileCfg
+
+	// Valid config - apply it
+	config.Set(newCfg)
+	if t, err := loadTheme(newCfg.UI.Theme); err == nil {
+		currentTheme.Set(t)
+	}
+	// Config reloaded successfully, notify the app
+	notifyConfigChange()
 }
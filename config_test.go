@@ -1,8 +1,15 @@
 package main
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"sync"
 	"testing"
+
+	"github.com/spf13/viper"
 )
 
 // TestSafeConfigConcurrency tests that SafeConfig can be safely accessed from multiple goroutines
@@ -128,14 +135,30 @@ func TestValidateConfig(t *testing.T) {
 		cfg.UI.Color = "2"
 		cfg.UI.ColorMode = "manual"
 		cfg.UI.MaxWidth = 45
+		cfg.UI.Palette = "vibrant"
+		cfg.UI.Background = "auto"
+		cfg.UI.Theme = "default"
+		cfg.UI.ColorProfile = "auto"
+		cfg.UI.Layout = "card"
+		cfg.UI.InlineHeight = 16
+		cfg.UI.Icons = "nerd"
+		cfg.UI.ProgressStyle = "block"
+		cfg.UI.TimeDisplay = "total"
 		cfg.Artwork.Enabled = true
 		cfg.Artwork.Padding = 15
 		cfg.Artwork.WidthPixels = 300
 		cfg.Artwork.WidthColumns = 13
+		cfg.Artwork.Animation = "none"
+		cfg.Artwork.Transition = "none"
+		cfg.Artwork.TransitionMs = 400
 		cfg.Artwork.VinylRPM = 33.33
 		cfg.Artwork.VinylFrames = 90
-		cfg.Text.MaxLengthWithArt = 22
-		cfg.Text.MaxLengthNoArt = 36
+		cfg.Artwork.VinylMemoryMB = 64
+		cfg.Artwork.Transmission = "direct"
+		cfg.Artwork.VinylPlayback = "auto"
+		cfg.Text.Overflow = "marquee"
+		cfg.Text.ScrollSpeed = 3
+		cfg.Text.ScrollPauseMs = 3000
 		cfg.Timing.UIRefreshMs = 100
 		cfg.Timing.DataFetchMs = 1000
 
@@ -154,8 +177,6 @@ func TestValidateConfig(t *testing.T) {
 		cfg.Artwork.WidthPixels = 300
 		cfg.Artwork.WidthColumns = 13
 		cfg.Artwork.VinylRPM = 33.33
-		cfg.Text.MaxLengthWithArt = 22
-		cfg.Text.MaxLengthNoArt = 36
 		cfg.Timing.UIRefreshMs = 100
 		cfg.Timing.DataFetchMs = 1000
 
@@ -174,8 +195,6 @@ func TestValidateConfig(t *testing.T) {
 		cfg.Artwork.WidthPixels = 300
 		cfg.Artwork.WidthColumns = 13
 		cfg.Artwork.VinylRPM = 33.33
-		cfg.Text.MaxLengthWithArt = 22
-		cfg.Text.MaxLengthNoArt = 36
 		cfg.Timing.UIRefreshMs = 100
 		cfg.Timing.DataFetchMs = 1000
 
@@ -194,8 +213,6 @@ func TestValidateConfig(t *testing.T) {
 		cfg.Artwork.WidthPixels = 300
 		cfg.Artwork.WidthColumns = 13
 		cfg.Artwork.VinylRPM = 33.33
-		cfg.Text.MaxLengthWithArt = 22
-		cfg.Text.MaxLengthNoArt = 36
 		cfg.Timing.UIRefreshMs = 100
 		cfg.Timing.DataFetchMs = 1000
 
@@ -214,8 +231,6 @@ func TestValidateConfig(t *testing.T) {
 		cfg.Artwork.WidthPixels = 300
 		cfg.Artwork.WidthColumns = 13
 		cfg.Artwork.VinylRPM = 33.33
-		cfg.Text.MaxLengthWithArt = 22
-		cfg.Text.MaxLengthNoArt = 36
 		cfg.Timing.UIRefreshMs = 100
 		cfg.Timing.DataFetchMs = 1000
 
@@ -234,8 +249,6 @@ func TestValidateConfig(t *testing.T) {
 		cfg.Artwork.WidthPixels = 300
 		cfg.Artwork.WidthColumns = 13
 		cfg.Artwork.VinylRPM = 33.33
-		cfg.Text.MaxLengthWithArt = 22
-		cfg.Text.MaxLengthNoArt = 36
 		cfg.Timing.UIRefreshMs = 100
 		cfg.Timing.DataFetchMs = 1000
 
@@ -254,8 +267,6 @@ func TestValidateConfig(t *testing.T) {
 		cfg.Artwork.WidthPixels = 0
 		cfg.Artwork.WidthColumns = 13
 		cfg.Artwork.VinylRPM = 33.33
-		cfg.Text.MaxLengthWithArt = 22
-		cfg.Text.MaxLengthNoArt = 36
 		cfg.Timing.UIRefreshMs = 100
 		cfg.Timing.DataFetchMs = 1000
 
@@ -274,8 +285,6 @@ func TestValidateConfig(t *testing.T) {
 		cfg.Artwork.WidthPixels = 300
 		cfg.Artwork.WidthColumns = 13
 		cfg.Artwork.VinylRPM = 33.33
-		cfg.Text.MaxLengthWithArt = 22
-		cfg.Text.MaxLengthNoArt = 36
 		cfg.Timing.UIRefreshMs = 5
 		cfg.Timing.DataFetchMs = 1000
 
@@ -285,6 +294,22 @@ func TestValidateConfig(t *testing.T) {
 		}
 	})
 
+	t.Run("unknown theme", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+		cfg := Config{}
+		cfg.UI.Theme = "no-such-theme"
+
+		found := false
+		for _, err := range validateConfig(&cfg) {
+			if configErr, ok := err.(configError); ok && configErr.field == "ui.theme" {
+				found = true
+			}
+		}
+		if !found {
+			t.Error("Expected error for unknown theme")
+		}
+	})
+
 	t.Run("multiple errors", func(t *testing.T) {
 		cfg := Config{}
 		cfg.UI.Color = "invalid"
@@ -293,8 +318,6 @@ func TestValidateConfig(t *testing.T) {
 		cfg.Artwork.Padding = -5
 		cfg.Artwork.WidthPixels = 0
 		cfg.Artwork.WidthColumns = 0
-		cfg.Text.MaxLengthWithArt = 0
-		cfg.Text.MaxLengthNoArt = 0
 		cfg.Timing.UIRefreshMs = 0
 		cfg.Timing.DataFetchMs = 0
 
@@ -314,8 +337,6 @@ func TestApplyDefaultsForInvalidFields(t *testing.T) {
 	cfg.Artwork.Padding = -5
 	cfg.Artwork.WidthPixels = 0
 	cfg.Artwork.WidthColumns = 0
-	cfg.Text.MaxLengthWithArt = 0
-	cfg.Text.MaxLengthNoArt = 0
 	cfg.Timing.UIRefreshMs = 0
 	cfg.Timing.DataFetchMs = 0
 
@@ -371,8 +392,8 @@ func TestValidationIntegration(t *testing.T) {
 	cfg.Artwork.WidthPixels = 0
 	cfg.Artwork.WidthColumns = 13
 	cfg.Artwork.VinylRPM = 33.33
-	cfg.Text.MaxLengthWithArt = 0
-	cfg.Text.MaxLengthNoArt = 300
+	cfg.UI.Layout = "sideways"
+	cfg.UI.Theme = "no/such/theme"
 	cfg.Timing.UIRefreshMs = 5
 	cfg.Timing.DataFetchMs = 70000
 
@@ -413,11 +434,11 @@ func TestValidationIntegration(t *testing.T) {
 	if cfg.Artwork.WidthPixels != 300 {
 		t.Errorf("Expected width_pixels default 300, got %d", cfg.Artwork.WidthPixels)
 	}
-	if cfg.Text.MaxLengthWithArt != 22 {
-		t.Errorf("Expected max_length_with_art default 22, got %d", cfg.Text.MaxLengthWithArt)
+	if cfg.UI.Layout != "card" {
+		t.Errorf("Expected layout default 'card', got '%s'", cfg.UI.Layout)
 	}
-	if cfg.Text.MaxLengthNoArt != 36 {
-		t.Errorf("Expected max_length_no_art default 36, got %d", cfg.Text.MaxLengthNoArt)
+	if cfg.UI.Theme != "default" {
+		t.Errorf("Expected theme default 'default', got '%s'", cfg.UI.Theme)
 	}
 	if cfg.Timing.UIRefreshMs != 100 {
 		t.Errorf("Expected ui_refresh_ms default 100, got %d", cfg.Timing.UIRefreshMs)
@@ -426,3 +447,75 @@ func TestValidationIntegration(t *testing.T) {
 		t.Errorf("Expected data_fetch_ms default 1000, got %d", cfg.Timing.DataFetchMs)
 	}
 }
+
+// TestReloadMalformedConfig verifies a config file that doesn't parse, or
+// doesn't unmarshal, is reported and the old config kept
+func TestReloadMalformedConfig(t *testing.T) {
+	defer config.Set(config.Get())
+	defer viper.Reset()
+	defer drainConfigChange()
+	drainConfigChange()
+	config.Set(layoutTestConfig(layoutCard))
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	viper.SetConfigFile(path)
+	for name, text := range map[string]string{
+		"yaml":      "ui:\n  max_width: [60\n",
+		"unmarshal": "ui:\n  max_width: wide\n",
+	} {
+		assertNoError(t, os.WriteFile(path, []byte(text), 0o644))
+		reloadConfig()
+		select {
+		case msg := <-configChangeChan:
+			assertEqual(t, len(msg.rejected), 1, name+" rejected")
+		default:
+			t.Errorf("%s: not reported", name)
+		}
+		assertEqual(t, config.Get().UI.MaxWidth, 45, name+" kept the old config")
+	}
+
+	updated, _ := model{}.Update(configReloadMsg{rejected: []error{errors.New("While parsing config: bad")}})
+	assertEqual(t, updated.(model).toast.text, "Config not reloaded: While parsing config: bad", "toast")
+}
+
+// setViperDefaults makes cfg viper's defaults, so a test config file only
+// needs the settings it's about
+func setViperDefaults(cfg Config) {
+	v := reflect.ValueOf(cfg)
+	for _, key := range configKeys() {
+		sectionName, fieldName, _ := strings.Cut(key, ".")
+		section, _ := fieldByTag(v, sectionName)
+		field, _ := fieldByTag(section, fieldName)
+		viper.SetDefault(key, field.Interface())
+	}
+}
+
+// TestReloadKeepsToggles verifies a reload keeps the time display 't'
+// switched to, unless the file's time_display was edited
+func TestReloadKeepsToggles(t *testing.T) {
+	defer config.Set(config.Get())
+	defer func(last Config) { lastFileCfg = last }(lastFileCfg)
+	defer viper.Reset()
+	defer drainConfigChange()
+
+	cfg := layoutTestConfig(layoutCard)
+	applyDefaultsForInvalidFields(&cfg, validateConfig(&cfg))
+	cfg.UI.TimeDisplay = timeTotal
+	setViperDefaults(cfg)
+	lastFileCfg = cfg
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	viper.SetConfigFile(path)
+
+	toggled := cfg
+	toggled.UI.TimeDisplay = nextTimeDisplay(cfg.UI.TimeDisplay)
+	config.Set(toggled)
+
+	assertNoError(t, os.WriteFile(path, []byte("ui:\n  time_display: total\n  max_width: 50\n"), 0o644))
+	reloadConfig()
+	assertEqual(t, config.Get().UI.MaxWidth, 50, "edit applied")
+	assertEqual(t, config.Get().UI.TimeDisplay, toggled.UI.TimeDisplay, "toggle kept")
+
+	assertNoError(t, os.WriteFile(path, []byte("ui:\n  time_display: remaining\n"), 0o644))
+	reloadConfig()
+	assertEqual(t, config.Get().UI.TimeDisplay, timeRemaining, "file edit wins")
+}
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"sort"
+	"time"
+
+	"github.com/nfnt/resize"
+	"golang.org/x/image/webp"
+)
+
+const (
+	// Limits on animated covers, so a huge GIF can't exhaust memory. Larger
+	// covers are shown as a still; longer ones play their first frames.
+	coverMaxDimension = 720 // Canvas width/height in pixels
+	coverMaxFrames    = 120 // Source frames decoded
+
+	// coverMaxSlots caps how many evenly spaced frames one loop is rendered
+	// with (frames with long delays repeat across slots)
+	coverMaxSlots = 240
+
+	// Frame delays: browsers play delays of 10ms or less at 100ms, and
+	// anything faster than coverMinDelay isn't worth a frame of its own
+	coverDefaultDelay = 100 * time.Millisecond
+	coverMinDelay     = 20 * time.Millisecond
+)
+
+// isAnimatedArtwork reports whether raw artwork is an animated GIF or WebP.
+// Only scans headers and block structure, so it's cheap on the UI goroutine.
+func isAnimatedArtwork(data []byte) bool {
+	if isWebP(data) {
+		flags, _, _, ok := webpCanvas(data)
+		return ok && flags&webpAnimationFlag != 0
+	}
+	ends, err := gifFrameEnds(data)
+	return err == nil && len(ends) > 1
+}
+
+// decodeArtworkFrames decodes up to maxFrames frames of an animated GIF or
+// WebP, composited onto the full canvas, calling yield with each frame and
+// how long it shows. The canvas is reused: yield must copy what it keeps.
+func decodeArtworkFrames(data []byte, maxFrames int, yield func(frame *image.RGBA, delay time.Duration)) error {
+	if isWebP(data) {
+		return decodeWebPFrames(data, maxFrames, yield)
+	}
+	return decodeGIFFrames(data, maxFrames, yield)
+}
+
+// frameDelay applies the browser convention for too-short delays
+func frameDelay(d time.Duration) time.Duration {
+	if d <= 10*time.Millisecond {
+		return coverDefaultDelay
+	}
+	return d
+}
+
+// gifFrameEnds walks a GIF's block structure without decompressing anything
+// and returns the offset just past each frame's image data
+func gifFrameEnds(data []byte) ([]int, error) {
+	if len(data) < 13 || !bytes.HasPrefix(data, []byte("GIF8")) {
+		return nil, fmt.Errorf("not a GIF")
+	}
+	pos := 13
+	if data[10]&0x80 != 0 {
+		// Global color table
+		pos += 3 << (data[10]&0x07 + 1)
+	}
+
+	// skipSubBlocks returns the offset after a sequence of data sub-blocks
+	skipSubBlocks := func(p int) (int, error) {
+		for {
+			if p >= len(data) {
+				return 0, fmt.Errorf("truncated GIF")
+			}
+			size := int(data[p])
+			p++
+			if size == 0 {
+				return p, nil
+			}
+			p += size
+		}
+	}
+
+	var ends []int
+	for pos < len(data) {
+		switch data[pos] {
+		case 0x21: // Extension: label, then sub-blocks
+			next, err := skipSubBlocks(pos + 2)
+			if err != nil {
+				return nil, err
+			}
+			pos = next
+		case 0x2C: // Image descriptor, optional local color table, LZW data
+			if pos+10 > len(data) {
+				return nil, fmt.Errorf("truncated GIF")
+			}
+			flags := data[pos+9]
+			pos += 10
+			if flags&0x80 != 0 {
+				pos += 3 << (flags&0x07 + 1)
+			}
+			next, err := skipSubBlocks(pos + 1) // Skip the LZW minimum code size
+			if err != nil {
+				return nil, err
+			}
+			pos = next
+			ends = append(ends, pos)
+		case 0x3B: // Trailer
+			return ends, nil
+		default:
+			return nil, fmt.Errorf("invalid GIF block 0x%02x", data[pos])
+		}
+	}
+	return ends, nil
+}
+
+// decodeGIFFrames composites up to maxFrames GIF frames, honouring each
+// frame's disposal method
+func decodeGIFFrames(data []byte, maxFrames int, yield func(*image.RGBA, time.Duration)) error {
+	cfg, err := gif.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if cfg.Width > coverMaxDimension || cfg.Height > coverMaxDimension {
+		return fmt.Errorf("animated cover too large (%dx%d)", cfg.Width, cfg.Height)
+	}
+
+	// Cut the stream after maxFrames frames so DecodeAll never holds more
+	ends, err := gifFrameEnds(data)
+	if err != nil {
+		return err
+	}
+	if len(ends) > maxFrames {
+		data = append(append([]byte(nil), data[:ends[maxFrames-1]]...), 0x3B)
+	}
+
+	anim, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, cfg.Width, cfg.Height))
+	var previous *image.RGBA
+	for i, frame := range anim.Image {
+		disposal := byte(gif.DisposalNone)
+		if i < len(anim.Disposal) {
+			disposal = anim.Disposal[i]
+		}
+		if disposal == gif.DisposalPrevious {
+			previous = cloneRGBA(canvas)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		yield(canvas, frameDelay(time.Duration(anim.Delay[i])*10*time.Millisecond))
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			copy(canvas.Pix, previous.Pix)
+		}
+	}
+	return nil
+}
+
+const (
+	webpAnimationFlag = 0x02 // VP8X flag: the file is animated
+	webpAlphaFlag     = 0x10 // VP8X flag: frames carry an ALPH chunk
+
+	webpFrameNoBlend = 0x02 // ANMF flag: replace the area instead of alpha-blending
+	webpFrameDispose = 0x01 // ANMF flag: clear the area after the frame is shown
+)
+
+func isWebP(data []byte) bool {
+	return len(data) >= 12 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WEBP"
+}
+
+// webpChunks calls fn with each top-level chunk of a WebP file (or each
+// sub-chunk of an ANMF payload), stopping early when fn returns false
+func webpChunks(data []byte, fn func(fourCC string, payload []byte) bool) {
+	for pos := 0; pos+8 <= len(data); {
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		end := pos + 8 + size
+		if size < 0 || end > len(data) {
+			return
+		}
+		if !fn(string(data[pos:pos+4]), data[pos+8:end]) {
+			return
+		}
+		// Chunks are padded to an even size
+		pos = end + size&1
+	}
+}
+
+// uint24 reads a little-endian 24-bit value
+func uint24(b []byte) int {
+	return int(b[0]) | int(b[1])<<8 | int(b[2])<<16
+}
+
+// webpCanvas returns the VP8X flags and canvas size of an extended WebP file
+func webpCanvas(data []byte) (flags byte, width int, height int, ok bool) {
+	webpChunks(data[12:], func(fourCC string, payload []byte) bool {
+		if fourCC == "VP8X" && len(payload) >= 10 {
+			flags = payload[0]
+			width = uint24(payload[4:7]) + 1
+			height = uint24(payload[7:10]) + 1
+			ok = true
+		}
+		return false // VP8X is always the first chunk
+	})
+	return flags, width, height, ok
+}
+
+// webpRIFF wraps chunks into a standalone WebP file
+func webpRIFF(chunks ...[]byte) []byte {
+	var body bytes.Buffer
+	body.WriteString("WEBP")
+	for _, c := range chunks {
+		body.Write(c)
+	}
+	out := make([]byte, 8, 8+body.Len())
+	copy(out, "RIFF")
+	binary.LittleEndian.PutUint32(out[4:], uint32(body.Len()))
+	return append(out, body.Bytes()...)
+}
+
+// webpChunk serializes one chunk, with padding
+func webpChunk(fourCC string, payload []byte) []byte {
+	out := make([]byte, 8, 8+len(payload)+1)
+	copy(out, fourCC)
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(payload)))
+	out = append(out, payload...)
+	if len(payload)%2 == 1 {
+		out = append(out, 0)
+	}
+	return out
+}
+
+// decodeWebPFrames composites up to maxFrames frames of an animated WebP.
+// x/image/webp only decodes stills, so each ANMF frame is re-wrapped as a
+// still WebP and decoded on its own.
+func decodeWebPFrames(data []byte, maxFrames int, yield func(*image.RGBA, time.Duration)) error {
+	flags, width, height, ok := webpCanvas(data)
+	if !ok || flags&webpAnimationFlag == 0 {
+		return fmt.Errorf("not an animated WebP")
+	}
+	if width > coverMaxDimension || height > coverMaxDimension {
+		return fmt.Errorf("animated cover too large (%dx%d)", width, height)
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	var decodeErr error
+	frames := 0
+	webpChunks(data[12:], func(fourCC string, payload []byte) bool {
+		if fourCC != "ANMF" || len(payload) < 16 {
+			return true
+		}
+		x, y := uint24(payload[0:3])*2, uint24(payload[3:6])*2
+		w, h := uint24(payload[6:9])+1, uint24(payload[9:12])+1
+		delay := time.Duration(uint24(payload[12:15])) * time.Millisecond
+		frameFlags := payload[15]
+
+		// Frame data: an optional ALPH chunk and a VP8 or VP8L chunk
+		var alph, bitstream []byte
+		webpChunks(payload[16:], func(sub string, subPayload []byte) bool {
+			switch sub {
+			case "ALPH":
+				alph = webpChunk(sub, subPayload)
+			case "VP8 ", "VP8L":
+				bitstream = webpChunk(sub, subPayload)
+			}
+			return true
+		})
+		if bitstream == nil {
+			decodeErr = fmt.Errorf("WebP frame without image data")
+			return false
+		}
+		still := webpRIFF(bitstream)
+		if alph != nil {
+			vp8x := make([]byte, 10)
+			vp8x[0] = webpAlphaFlag
+			vp8x[4], vp8x[5], vp8x[6] = byte(w-1), byte((w-1)>>8), byte((w-1)>>16)
+			vp8x[7], vp8x[8], vp8x[9] = byte(h-1), byte((h-1)>>8), byte((h-1)>>16)
+			still = webpRIFF(webpChunk("VP8X", vp8x), alph, bitstream)
+		}
+		img, err := webp.Decode(bytes.NewReader(still))
+		if err != nil {
+			decodeErr = err
+			return false
+		}
+
+		area := image.Rect(x, y, x+w, y+h)
+		op := draw.Over
+		if frameFlags&webpFrameNoBlend != 0 {
+			op = draw.Src
+		}
+		draw.Draw(canvas, area, img, img.Bounds().Min, op)
+		yield(canvas, frameDelay(delay))
+
+		if frameFlags&webpFrameDispose != 0 {
+			draw.Draw(canvas, area, image.Transparent, image.Point{}, draw.Src)
+		}
+		frames++
+		return frames < maxFrames
+	})
+	if decodeErr != nil {
+		return decodeErr
+	}
+	if frames == 0 {
+		return fmt.Errorf("animated WebP without frames")
+	}
+	return nil
+}
+
+// decodeFirstArtworkFrame decodes just the first frame of an animated cover
+// (for formats image.Decode can't read, like animated WebP)
+func decodeFirstArtworkFrame(data []byte) (image.Image, error) {
+	var first *image.RGBA
+	err := decodeArtworkFrames(data, 1, func(frame *image.RGBA, _ time.Duration) {
+		first = cloneRGBA(frame)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return first, nil
+}
+
+// coverAnimation plays an animated cover (artwork.animated_covers). Source
+// frames have their own delays, so the loop is resampled onto evenly spaced
+// slots like every other animation: frame(i, total) shows whichever source
+// frame is on screen i/total of the way through the loop.
+type coverAnimation struct {
+	frames []*image.RGBA   // Resized, filtered source frames
+	starts []time.Duration // When each source frame starts within the loop
+	loop   time.Duration   // Total loop duration
+	slots  int             // Evenly spaced frames per loop
+}
+
+// newCoverAnimation decodes, resizes and filters an animated cover's frames
+func newCoverAnimation(data []byte, cfg Config) (*coverAnimation, error) {
+	c := &coverAnimation{}
+	shortest := time.Duration(0)
+	err := decodeArtworkFrames(data, coverMaxFrames, func(frame *image.RGBA, delay time.Duration) {
+		resized := toRGBA(resize.Resize(uint(cfg.Artwork.WidthPixels), 0, frame, resize.Lanczos3))
+		c.frames = append(c.frames, applyFilters(resized, cfg))
+		c.starts = append(c.starts, c.loop)
+		c.loop += delay
+		if shortest == 0 || delay < shortest {
+			shortest = delay
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(c.frames) < 2 {
+		return nil, fmt.Errorf("cover isn't animated")
+	}
+
+	// One slot per shortest delay, so no source frame is skipped
+	if shortest < coverMinDelay {
+		shortest = coverMinDelay
+	}
+	c.slots = int((c.loop + shortest - 1) / shortest)
+	if c.slots > coverMaxSlots {
+		c.slots = coverMaxSlots
+	}
+	if c.slots < animMinFrames {
+		c.slots = animMinFrames
+	}
+	return c, nil
+}
+
+// prepare is unused: frames come from the decoded cover, not the still
+func (c *coverAnimation) prepare(img image.Image, cfg Config) *image.RGBA {
+	return c.frames[0]
+}
+
+func (c *coverAnimation) frame(prepared *image.RGBA, index int, total int) image.Image {
+	at := time.Duration(int64(c.loop) * int64(index) / int64(total))
+	// Last source frame starting at or before that point
+	k := sort.Search(len(c.starts), func(i int) bool { return c.starts[i] > at }) - 1
+	return c.frames[k]
+}
+
+func (c *coverAnimation) maxFrames(cfg Config) int {
+	return c.slots
+}
+
+func (c *coverAnimation) cycle(cfg Config) time.Duration {
+	return c.loop
+}
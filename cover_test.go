@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	colorpalette "image/color/palette"
+	"image/draw"
+	"image/gif"
+	"testing"
+	"time"
+)
+
+// gifFrame is one frame of a test GIF
+type gifFrame struct {
+	rect     image.Rectangle
+	fill     color.Color
+	delay    int // Hundredths of a second
+	disposal byte
+}
+
+// generateTestGIF encodes an animated GIF of solid-colored frames
+func generateTestGIF(t *testing.T, width, height int, frames []gifFrame) []byte {
+	t.Helper()
+	anim := &gif.GIF{Config: image.Config{Width: width, Height: height, ColorModel: color.Palette(colorpalette.Plan9)}}
+	for _, f := range frames {
+		img := image.NewPaletted(f.rect, colorpalette.Plan9)
+		draw.Draw(img, f.rect, image.NewUniform(f.fill), image.Point{}, draw.Src)
+		anim.Image = append(anim.Image, img)
+		anim.Delay = append(anim.Delay, f.delay)
+		anim.Disposal = append(anim.Disposal, f.disposal)
+	}
+	var buf bytes.Buffer
+	assertNoError(t, gif.EncodeAll(&buf, anim))
+	return buf.Bytes()
+}
+
+// solidVP8L encodes a solid-colored lossless WebP bitstream: every prefix
+// code has a single symbol, so the pixels themselves take no bits at all
+func solidVP8L(width, height int, c color.NRGBA) []byte {
+	var out []byte
+	var acc uint64
+	var n uint
+	put := func(v uint64, bits uint) {
+		acc |= v << n
+		n += bits
+		for n >= 8 {
+			out = append(out, byte(acc))
+			acc >>= 8
+			n -= 8
+		}
+	}
+	put(0x2f, 8)
+	put(uint64(width-1), 14)
+	put(uint64(height-1), 14)
+	put(1, 1) // Alpha used
+	put(0, 3) // Version
+	put(0, 1) // No transforms
+	put(0, 1) // No color cache
+	put(0, 1) // No meta prefix codes
+	// Green, red, blue, alpha and distance codes: simple, one 8-bit symbol
+	for _, sym := range []uint8{c.G, c.R, c.B, c.A, 0} {
+		put(1, 1)
+		put(0, 1)
+		put(1, 1)
+		put(uint64(sym), 8)
+	}
+	if n > 0 {
+		out = append(out, byte(acc))
+	}
+	return out
+}
+
+// webpFrame is one ANMF frame of a test WebP
+type webpFrame struct {
+	x, y, w, h int
+	fill       color.NRGBA
+	delayMs    int
+	flags      byte
+}
+
+// generateTestWebP assembles an animated WebP from solid VP8L frames
+func generateTestWebP(width, height int, frames []webpFrame) []byte {
+	put24 := func(b []byte, v int) {
+		b[0], b[1], b[2] = byte(v), byte(v>>8), byte(v>>16)
+	}
+	vp8x := make([]byte, 10)
+	vp8x[0] = webpAnimationFlag | webpAlphaFlag
+	put24(vp8x[4:], width-1)
+	put24(vp8x[7:], height-1)
+	chunks := [][]byte{webpChunk("VP8X", vp8x), webpChunk("ANIM", make([]byte, 6))}
+
+	for _, f := range frames {
+		header := make([]byte, 16)
+		put24(header[0:], f.x/2)
+		put24(header[3:], f.y/2)
+		put24(header[6:], f.w-1)
+		put24(header[9:], f.h-1)
+		put24(header[12:], f.delayMs)
+		header[15] = f.flags
+		payload := append(header, webpChunk("VP8L", solidVP8L(f.w, f.h, f.fill))...)
+		chunks = append(chunks, webpChunk("ANMF", payload))
+	}
+	return webpRIFF(chunks...)
+}
+
+// collectFrames decodes every frame of an animated cover, keeping copies
+func collectFrames(t *testing.T, data []byte, maxFrames int) ([]*image.RGBA, []time.Duration) {
+	t.Helper()
+	var frames []*image.RGBA
+	var delays []time.Duration
+	err := decodeArtworkFrames(data, maxFrames, func(frame *image.RGBA, delay time.Duration) {
+		frames = append(frames, cloneRGBA(frame))
+		delays = append(delays, delay)
+	})
+	assertNoError(t, err)
+	return frames, delays
+}
+
+// TestDecodeAnimatedGIF verifies GIF frames are composited with their
+// disposal methods and keep their delays
+func TestDecodeAnimatedGIF(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	green := color.RGBA{0, 255, 0, 255}
+	data := generateTestGIF(t, 4, 4, []gifFrame{
+		{image.Rect(0, 0, 4, 4), red, 10, gif.DisposalNone},
+		{image.Rect(2, 2, 4, 4), blue, 0, gif.DisposalPrevious},
+		{image.Rect(0, 0, 2, 2), green, 25, gif.DisposalNone},
+	})
+
+	if !isAnimatedArtwork(data) {
+		t.Fatal("three-frame GIF not detected as animated")
+	}
+	frames, delays := collectFrames(t, data, coverMaxFrames)
+	if len(frames) != 3 {
+		t.Fatalf("got %d frames, want 3", len(frames))
+	}
+
+	wantDelays := []time.Duration{100 * time.Millisecond, coverDefaultDelay, 250 * time.Millisecond}
+	for i, want := range wantDelays {
+		if delays[i] != want {
+			t.Errorf("frame %d: delay %v, want %v", i, delays[i], want)
+		}
+	}
+
+	// Frame 1 draws over frame 0; its disposal restores frame 0 underneath frame 2
+	if got := frames[1].RGBAAt(3, 3); got != blue {
+		t.Errorf("frame 1 corner = %v, want blue", got)
+	}
+	if got := frames[2].RGBAAt(3, 3); got != red {
+		t.Errorf("frame 2 corner = %v, want red (frame 1 disposed)", got)
+	}
+	if got := frames[2].RGBAAt(0, 0); got != green {
+		t.Errorf("frame 2 origin = %v, want green", got)
+	}
+}
+
+// TestAnimatedCoverLimits verifies the frame and dimension caps
+func TestAnimatedCoverLimits(t *testing.T) {
+	frames := make([]gifFrame, coverMaxFrames+5)
+	for i := range frames {
+		frames[i] = gifFrame{image.Rect(0, 0, 2, 2), color.RGBA{uint8(i), 0, 0, 255}, 5, gif.DisposalNone}
+	}
+	decoded, _ := collectFrames(t, generateTestGIF(t, 2, 2, frames), coverMaxFrames)
+	if len(decoded) != coverMaxFrames {
+		t.Errorf("got %d frames, want the %d cap", len(decoded), coverMaxFrames)
+	}
+
+	huge := generateTestGIF(t, coverMaxDimension+1, 2, []gifFrame{
+		{image.Rect(0, 0, 2, 2), color.Black, 5, gif.DisposalNone},
+		{image.Rect(0, 0, 2, 2), color.White, 5, gif.DisposalNone},
+	})
+	err := decodeArtworkFrames(huge, coverMaxFrames, func(*image.RGBA, time.Duration) {})
+	assertError(t, err, "cover larger than coverMaxDimension")
+
+	still := generateTestGIF(t, 2, 2, frames[:1])
+	if isAnimatedArtwork(still) {
+		t.Error("single-frame GIF detected as animated")
+	}
+}
+
+// TestDecodeAnimatedWebP verifies ANMF frames decode, blend and dispose, and
+// that the still path falls back to the first frame
+func TestDecodeAnimatedWebP(t *testing.T) {
+	red := color.NRGBA{255, 0, 0, 255}
+	blue := color.NRGBA{0, 0, 255, 255}
+	data := generateTestWebP(4, 4, []webpFrame{
+		{0, 0, 4, 4, red, 80, 0},
+		{2, 2, 2, 2, blue, 40, webpFrameDispose},
+		{0, 0, 2, 2, color.NRGBA{}, 120, webpFrameNoBlend},
+	})
+
+	if !isAnimatedArtwork(data) {
+		t.Fatal("animated WebP not detected")
+	}
+	frames, delays := collectFrames(t, data, coverMaxFrames)
+	if len(frames) != 3 {
+		t.Fatalf("got %d frames, want 3", len(frames))
+	}
+	if delays[0] != 80*time.Millisecond || delays[2] != 120*time.Millisecond {
+		t.Errorf("delays = %v", delays)
+	}
+
+	opaqueRed := color.RGBA{255, 0, 0, 255}
+	if got := frames[1].RGBAAt(3, 3); got != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("frame 1 corner = %v, want blue", got)
+	}
+	if got := frames[2].RGBAAt(3, 3); got != (color.RGBA{}) {
+		t.Errorf("frame 2 corner = %v, want cleared by frame 1's disposal", got)
+	}
+	if got := frames[2].RGBAAt(0, 0); got != (color.RGBA{}) {
+		t.Errorf("frame 2 origin = %v, want transparent (no blending)", got)
+	}
+	if got := frames[2].RGBAAt(3, 0); got != opaqueRed {
+		t.Errorf("frame 2 untouched area = %v, want red", got)
+	}
+
+	img, err := decodeArtworkData(data)
+	assertNoError(t, err)
+	if got := color.RGBAModel.Convert(img.At(0, 0)); got != opaqueRed {
+		t.Errorf("still = %v, want the first frame", got)
+	}
+}
+
+// TestCoverAnimationTiming verifies uneven frame delays map onto evenly
+// spaced slots
+func TestCoverAnimationTiming(t *testing.T) {
+	data := generateTestGIF(t, 4, 4, []gifFrame{
+		{image.Rect(0, 0, 4, 4), color.RGBA{255, 0, 0, 255}, 10, gif.DisposalNone},
+		{image.Rect(0, 0, 4, 4), color.RGBA{0, 0, 255, 255}, 30, gif.DisposalNone},
+	})
+	cfg := Config{}
+	cfg.Artwork.WidthPixels = 4
+
+	cover, err := newCoverAnimation(data, cfg)
+	assertNoError(t, err)
+	if cover.cycle(cfg) != 400*time.Millisecond {
+		t.Errorf("cycle = %v, want 400ms", cover.cycle(cfg))
+	}
+	// One slot per shortest delay: 100ms
+	if cover.maxFrames(cfg) != 4 {
+		t.Fatalf("slots = %d, want 4", cover.maxFrames(cfg))
+	}
+	want := []int{0, 1, 1, 1}
+	for i, k := range want {
+		if got := cover.frame(nil, i, 4); got != cover.frames[k] {
+			t.Errorf("slot %d: wrong source frame, want %d", i, k)
+		}
+	}
+
+	_, err = newCoverAnimation(generateTestGIF(t, 4, 4, []gifFrame{
+		{image.Rect(0, 0, 4, 4), color.Black, 10, gif.DisposalNone},
+	}), cfg)
+	assertError(t, err, "still GIF isn't an animated cover")
+}
+
+// TestCoverActive verifies animated covers play only when enabled and no
+// animation mode is chosen
+func TestCoverActive(t *testing.T) {
+	m := model{animatedCover: true}
+	cfg := Config{}
+	cfg.Artwork.AnimatedCovers = true
+	cfg.Artwork.Animation = animationNone
+	if !m.coverActive(cfg) || m.animationKey(cfg) != "cover" {
+		t.Error("animated cover should play")
+	}
+
+	cfg.Artwork.Animation = "vinyl"
+	if m.coverActive(cfg) || m.animationKey(cfg) != "vinyl" {
+		t.Error("a chosen animation should take over from the cover")
+	}
+
+	cfg.Artwork.Animation = animationNone
+	cfg.Artwork.AnimatedCovers = false
+	if m.coverActive(cfg) {
+		t.Error("animated_covers: false should keep the cover still")
+	}
+}
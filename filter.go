@@ -0,0 +1,329 @@
+package main
+
+import (
+	"image"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/nfnt/resize"
+)
+
+const (
+	// Filter strengths, relative to the artwork's size where it matters
+	filterBlurRadius   = 0.02 // Box blur radius (three passes approximate a Gaussian)
+	filterPixelBlocks  = 24   // Blocks across the artwork when pixelated
+	filterVignette     = 0.55 // How dark the corners get (0-1)
+	filterCornerRadius = 0.08 // Rounded corner radius
+	filterDuotoneDark  = 0.12 // Shadows: the accent scaled down to this brightness
+	filterDuotoneLight = 0.45 // Highlights: the accent mixed this far toward white
+)
+
+// artworkFilters maps artwork.filters names to their implementation. Each
+// returns a new image and leaves its input untouched (the input may be the
+// cached artwork).
+var artworkFilters = map[string]func(img *image.RGBA, accent [3]uint8) *image.RGBA{
+	"blur":      blurFilter,
+	"pixelate":  pixelateFilter,
+	"grayscale": grayscaleFilter,
+	"duotone":   duotoneFilter,
+	"vignette":  vignetteFilter,
+	"rounded":   roundedFilter,
+}
+
+// artworkFilterNames lists the filters in the order the docs present them
+var artworkFilterNames = []string{"blur", "pixelate", "grayscale", "duotone", "vignette", "rounded"}
+
+// applyFilters runs the configured filter chain over resized artwork, in order
+func applyFilters(img *image.RGBA, cfg Config) *image.RGBA {
+	if len(cfg.Artwork.Filters) == 0 {
+		return img
+	}
+
+	var accent [3]uint8
+	for _, name := range cfg.Artwork.Filters {
+		if name == "duotone" {
+			accent = filterAccent(img, cfg)
+			break
+		}
+	}
+
+	for _, name := range cfg.Artwork.Filters {
+		if filter, ok := artworkFilters[name]; ok {
+			img = filter(img, accent)
+		}
+	}
+	return img
+}
+
+// filterAccent picks the duotone tint: the manual UI color when one is set as
+// hex, otherwise the accent extracted from the artwork (as in auto color mode)
+func filterAccent(img image.Image, cfg Config) [3]uint8 {
+	if cfg.UI.ColorMode == "manual" {
+		if rgb, ok := parseHexColor(cfg.UI.Color); ok {
+			return rgb
+		}
+	}
+	if hex, err := extractDominantColorOn(img, backgroundFor(cfg)); err == nil {
+		if rgb, ok := parseHexColor(hex); ok {
+			return rgb
+		}
+	}
+	// Nothing vibrant to tint with: fall back to a plain light gray
+	return [3]uint8{200, 200, 200}
+}
+
+// parseHexColor parses #RRGGBB or #RGB
+func parseHexColor(s string) ([3]uint8, bool) {
+	if !isValidColor(s) || !strings.HasPrefix(s, "#") {
+		return [3]uint8{}, false
+	}
+	hex := s[1:]
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	var rgb [3]uint8
+	for i := 0; i < 3; i++ {
+		var v uint8
+		for _, c := range hex[i*2 : i*2+2] {
+			v <<= 4
+			switch {
+			case c >= '0' && c <= '9':
+				v |= uint8(c - '0')
+			case c >= 'a' && c <= 'f':
+				v |= uint8(c-'a') + 10
+			case c >= 'A' && c <= 'F':
+				v |= uint8(c-'A') + 10
+			}
+		}
+		rgb[i] = v
+	}
+	return rgb, true
+}
+
+// blurFilter softens the artwork with three box blur passes each way
+func blurFilter(img *image.RGBA, _ [3]uint8) *image.RGBA {
+	radius := int(math.Round(float64(img.Rect.Dx()) * filterBlurRadius))
+	if radius < 1 {
+		radius = 1
+	}
+	out := cloneRGBA(img)
+	tmp := image.NewRGBA(img.Rect)
+	for pass := 0; pass < 3; pass++ {
+		boxBlur(out, tmp, radius, true)
+		boxBlur(tmp, out, radius, false)
+	}
+	return out
+}
+
+// boxBlur averages each pixel of src with its neighbours within radius along
+// one axis into dst, clamping at the edges
+func boxBlur(src, dst *image.RGBA, radius int, horizontal bool) {
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	lines, length := h, w
+	if !horizontal {
+		lines, length = w, h
+	}
+	offset := func(line, i int) int {
+		if i < 0 {
+			i = 0
+		} else if i >= length {
+			i = length - 1
+		}
+		if horizontal {
+			return src.PixOffset(i, line)
+		}
+		return src.PixOffset(line, i)
+	}
+
+	window := float64(2*radius + 1)
+	for line := 0; line < lines; line++ {
+		// Running sum over the window, slid one pixel at a time
+		var sum [4]int
+		for i := -radius; i <= radius; i++ {
+			o := offset(line, i)
+			for c := 0; c < 4; c++ {
+				sum[c] += int(src.Pix[o+c])
+			}
+		}
+		for i := 0; i < length; i++ {
+			o := offset(line, i)
+			for c := 0; c < 4; c++ {
+				dst.Pix[o+c] = uint8(float64(sum[c])/window + 0.5)
+			}
+			in, out := offset(line, i+radius+1), offset(line, i-radius)
+			for c := 0; c < 4; c++ {
+				sum[c] += int(src.Pix[in+c]) - int(src.Pix[out+c])
+			}
+		}
+	}
+}
+
+// pixelateFilter averages the artwork into large square blocks
+func pixelateFilter(img *image.RGBA, _ [3]uint8) *image.RGBA {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	block := w / filterPixelBlocks
+	if block < 2 {
+		block = 2
+	}
+	out := image.NewRGBA(img.Rect)
+	for by := 0; by < h; by += block {
+		for bx := 0; bx < w; bx += block {
+			var sum [4]int
+			n := 0
+			for y := by; y < by+block && y < h; y++ {
+				for x := bx; x < bx+block && x < w; x++ {
+					o := img.PixOffset(x, y)
+					for c := 0; c < 4; c++ {
+						sum[c] += int(img.Pix[o+c])
+					}
+					n++
+				}
+			}
+			for y := by; y < by+block && y < h; y++ {
+				for x := bx; x < bx+block && x < w; x++ {
+					o := out.PixOffset(x, y)
+					for c := 0; c < 4; c++ {
+						out.Pix[o+c] = uint8((sum[c] + n/2) / n)
+					}
+				}
+			}
+		}
+	}
+	return out
+}
+
+// luma returns the Rec. 709 brightness of a premultiplied pixel (0-255)
+func luma(px []uint8) float64 {
+	return 0.2126*float64(px[0]) + 0.7152*float64(px[1]) + 0.0722*float64(px[2])
+}
+
+// grayscaleFilter drops the artwork's color
+func grayscaleFilter(img *image.RGBA, _ [3]uint8) *image.RGBA {
+	out := cloneRGBA(img)
+	for i := 0; i < len(out.Pix); i += 4 {
+		l := uint8(luma(out.Pix[i:i+4:i+4]) + 0.5)
+		out.Pix[i], out.Pix[i+1], out.Pix[i+2] = l, l, l
+	}
+	return out
+}
+
+// duotoneFilter maps brightness onto a ramp from a dark to a light shade of
+// the accent color
+func duotoneFilter(img *image.RGBA, accent [3]uint8) *image.RGBA {
+	var dark, light [3]float64
+	for c := 0; c < 3; c++ {
+		dark[c] = float64(accent[c]) * filterDuotoneDark
+		light[c] = float64(accent[c]) + (255-float64(accent[c]))*filterDuotoneLight
+	}
+
+	out := cloneRGBA(img)
+	for i := 0; i < len(out.Pix); i += 4 {
+		px := out.Pix[i : i+4 : i+4]
+		alpha := float64(px[3]) / 255
+		if alpha == 0 {
+			continue
+		}
+		// Brightness of the unpremultiplied color
+		t := luma(px) / 255 / alpha
+		for c := 0; c < 3; c++ {
+			px[c] = uint8((dark[c]+(light[c]-dark[c])*t)*alpha + 0.5)
+		}
+	}
+	return out
+}
+
+// vignetteFilter darkens the artwork toward its corners
+func vignetteFilter(img *image.RGBA, _ [3]uint8) *image.RGBA {
+	out := cloneRGBA(img)
+	w, h := float64(out.Rect.Dx()), float64(out.Rect.Dy())
+	maxDist := math.Hypot(w/2, h/2)
+	for y := 0; y < out.Rect.Dy(); y++ {
+		for x := 0; x < out.Rect.Dx(); x++ {
+			// 0 at the center, 1 in the corners; no darkening inside half-way
+			d := math.Hypot(float64(x)+0.5-w/2, float64(y)+0.5-h/2) / maxDist
+			t := clamp01((d - 0.5) / 0.5)
+			fade := t * t * (3 - 2*t) // smoothstep
+			o := out.PixOffset(x, y)
+			scalePixel(out.Pix[o:o+4:o+4], 1-filterVignette*fade)
+		}
+	}
+	return out
+}
+
+// roundedFilter rounds the artwork's corners (transparent, anti-aliased)
+func roundedFilter(img *image.RGBA, _ [3]uint8) *image.RGBA {
+	out := cloneRGBA(img)
+	w, h := float64(out.Rect.Dx()), float64(out.Rect.Dy())
+	radius := math.Min(w, h) * filterCornerRadius
+	for y := 0; y < out.Rect.Dy(); y++ {
+		for x := 0; x < out.Rect.Dx(); x++ {
+			c := roundedRectCoverage(float64(x)+0.5, float64(y)+0.5, 0, 0, w, h, radius)
+			if c >= 1 {
+				continue
+			}
+			// RGBA is alpha-premultiplied, so scale every channel
+			o := out.PixOffset(x, y)
+			for ch := 0; ch < 4; ch++ {
+				out.Pix[o+ch] = uint8(float64(out.Pix[o+ch])*c + 0.5)
+			}
+		}
+	}
+	return out
+}
+
+// baseArtworkKey identifies a resized, filtered artwork
+type baseArtworkKey struct {
+	hash       uint64   // Hash of the raw artwork data
+	width      int      // artwork.width_pixels
+	filters    string   // artwork.filters, joined
+	accent     string   // UI color a manual duotone is tinted with
+	background [3]uint8 // Background an auto duotone's tint is picked for
+}
+
+// baseArtworkCache holds the current track's resized and filtered artwork, so
+// the still and every animation job share one resize and filter pass
+type baseArtworkCache struct {
+	mu  sync.Mutex
+	key baseArtworkKey
+	img *image.RGBA
+}
+
+var baseArtwork = &baseArtworkCache{}
+
+// get returns the resized, filtered artwork for img, computing it only when
+// the artwork or the settings it depends on have changed. artworkHash 0
+// bypasses the cache. The result is shared: callers must not modify it.
+func (c *baseArtworkCache) get(img image.Image, artworkHash uint64, cfg Config) *image.RGBA {
+	key := baseArtworkKey{
+		hash:       artworkHash,
+		width:      cfg.Artwork.WidthPixels,
+		filters:    strings.Join(cfg.Artwork.Filters, ","),
+		accent:     cfg.UI.ColorMode + cfg.UI.Color,
+		background: backgroundFor(cfg),
+	}
+	if artworkHash != 0 {
+		c.mu.Lock()
+		cached := c.img
+		if c.key != key {
+			cached = nil
+		}
+		c.mu.Unlock()
+		if cached != nil {
+			return cached
+		}
+	}
+
+	// Resize maintaining aspect ratio - keep it reasonable for terminal display
+	// We'll let Kitty handle the final sizing based on cell dimensions
+	resized := toRGBA(resize.Resize(uint(cfg.Artwork.WidthPixels), 0, img, resize.Lanczos3))
+	filtered := applyFilters(resized, cfg)
+
+	if artworkHash != 0 {
+		c.mu.Lock()
+		c.key = key
+		c.img = filtered
+		c.mu.Unlock()
+	}
+	return filtered
+}
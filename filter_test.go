@@ -0,0 +1,165 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// TestArtworkFilters tests each filter's visible effect
+func TestArtworkFilters(t *testing.T) {
+	// Left half red, right half blue
+	img := generateTestImage(96, 96, color.RGBA{220, 40, 40, 255})
+	for y := 0; y < 96; y++ {
+		for x := 48; x < 96; x++ {
+			img.SetRGBA(x, y, color.RGBA{40, 40, 220, 255})
+		}
+	}
+	before := append([]uint8(nil), img.Pix...)
+	accent := [3]uint8{0, 200, 100}
+
+	t.Run("grayscale", func(t *testing.T) {
+		px := grayscaleFilter(img, accent).RGBAAt(10, 10)
+		if px.R != px.G || px.G != px.B {
+			t.Errorf("Expected gray pixel, got %v", px)
+		}
+	})
+
+	t.Run("blur", func(t *testing.T) {
+		out := blurFilter(img, accent)
+		// The hard edge between the halves gets mixed
+		if px := out.RGBAAt(47, 48); px.R >= 220 || px.B <= 40 {
+			t.Errorf("Expected a blended pixel at the edge, got %v", px)
+		}
+		if px := out.RGBAAt(5, 48); px != (color.RGBA{220, 40, 40, 255}) {
+			t.Errorf("Expected pixels far from the edge unchanged, got %v", px)
+		}
+	})
+
+	t.Run("pixelate", func(t *testing.T) {
+		out := pixelateFilter(img, accent)
+		block := 96 / filterPixelBlocks
+		if out.RGBAAt(0, 0) != out.RGBAAt(block-1, block-1) {
+			t.Error("Expected pixels within a block to match")
+		}
+	})
+
+	t.Run("duotone", func(t *testing.T) {
+		px := duotoneFilter(img, accent).RGBAAt(10, 10)
+		// Tinted green-ish like the accent, whatever the original hue
+		if px.G <= px.R || px.G <= px.B {
+			t.Errorf("Expected accent-tinted pixel, got %v", px)
+		}
+	})
+
+	t.Run("vignette", func(t *testing.T) {
+		out := vignetteFilter(img, accent)
+		if out.RGBAAt(0, 0).R >= out.RGBAAt(40, 40).R {
+			t.Error("Expected corners darker than the middle")
+		}
+	})
+
+	t.Run("rounded", func(t *testing.T) {
+		out := roundedFilter(img, accent)
+		if a := out.RGBAAt(0, 0).A; a != 0 {
+			t.Errorf("Expected transparent corner, got alpha %d", a)
+		}
+		if a := out.RGBAAt(48, 0).A; a != 255 {
+			t.Errorf("Expected opaque edge middle, got alpha %d", a)
+		}
+	})
+
+	for i := range before {
+		if img.Pix[i] != before[i] {
+			t.Fatal("Expected filters to leave their input untouched")
+		}
+	}
+}
+
+// TestApplyFiltersChain tests filters run in the configured order
+func TestApplyFiltersChain(t *testing.T) {
+	img := generateGradientImage(64, 64, color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255})
+
+	cfg := Config{}
+	if applyFilters(img, cfg) != img {
+		t.Error("Expected no filters to return the artwork as is")
+	}
+
+	cfg.Artwork.Filters = []string{"grayscale", "rounded"}
+	out := applyFilters(img, cfg)
+	if px := out.RGBAAt(32, 32); px.R != px.G || px.G != px.B {
+		t.Errorf("Expected grayscale pixel, got %v", px)
+	}
+	if a := out.RGBAAt(0, 0).A; a != 0 {
+		t.Errorf("Expected rounded corner, got alpha %d", a)
+	}
+}
+
+// TestParseHexColor tests parsing the hex colors used to tint duotone
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		input string
+		want  [3]uint8
+		ok    bool
+	}{
+		{"#ff8000", [3]uint8{255, 128, 0}, true},
+		{"#F80", [3]uint8{255, 136, 0}, true},
+		{"2", [3]uint8{}, false},
+		{"nope", [3]uint8{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseHexColor(tt.input)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseHexColor(%q) = %v, %v; want %v, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// TestBaseArtworkCache tests resized, filtered artwork is computed once per
+// artwork and recomputed when the filters change
+func TestBaseArtworkCache(t *testing.T) {
+	img := generateTestImage(100, 100, color.RGBA{200, 100, 50, 255})
+	cfg := Config{}
+	cfg.Artwork.WidthPixels = 50
+	cfg.Artwork.Filters = []string{"vignette"}
+
+	cache := &baseArtworkCache{}
+	first := cache.get(img, 1, cfg)
+	if first.Rect.Dx() != 50 {
+		t.Errorf("Expected artwork resized to 50px, got %d", first.Rect.Dx())
+	}
+	if cache.get(img, 1, cfg) != first {
+		t.Error("Expected the same artwork and filters to hit the cache")
+	}
+
+	cfg.Artwork.Filters = []string{"grayscale"}
+	grayscale := cache.get(img, 1, cfg)
+	if grayscale == first {
+		t.Error("Expected changed filters to recompute")
+	}
+	cfg.UI.Background = backgroundLight
+	if cache.get(img, 1, cfg) == grayscale {
+		t.Error("Expected a background switch to recompute")
+	}
+	if cache.get(img, 0, cfg) == cache.get(img, 0, cfg) {
+		t.Error("Expected hash 0 to bypass the cache")
+	}
+}
+
+// TestFiltersComposeWithVinyl tests filters apply before vinyl's circle crop
+func TestFiltersComposeWithVinyl(t *testing.T) {
+	img := generateTestImage(100, 100, color.RGBA{200, 100, 50, 255})
+	cfg := Config{}
+	cfg.Artwork.WidthPixels = 100
+	cfg.Artwork.Animation = "vinyl"
+	cfg.Artwork.Filters = []string{"grayscale"}
+
+	record := prepareArtwork(img, 0, cfg).(*image.RGBA)
+	if a := record.RGBAAt(0, 0).A; a != 0 {
+		t.Errorf("Expected circle-cropped corner, got alpha %d", a)
+	}
+	if px := record.RGBAAt(50, 50); px.R != px.G || px.G != px.B || px.A != 255 {
+		t.Errorf("Expected filtered record center, got %v", px)
+	}
+}
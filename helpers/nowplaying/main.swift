@@ -192,7 +192,7 @@ func sendCommand(_ command: MRCommand) {
 // Main
 guard CommandLine.arguments.count > 1 else {
     fputs("Usage: nowplaying <command>\n", stderr)
-    fputs("Commands: metadata, duration, position, artwork, play-pause, next, previous\n", stderr)
+    fputs("Commands: metadata, duration, position, artwork, play-pause, pause, next, previous\n", stderr)
     exit(1)
 }
 
@@ -209,6 +209,8 @@ case "artwork":
     getArtwork()
 case "play-pause":
     sendCommand(.togglePlayPause)
+case "pause":
+    sendCommand(.pause)
 case "next":
     sendCommand(.nextTrack)
 case "previous":
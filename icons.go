@@ -0,0 +1,112 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// iconSet is every glyph the UI draws, for one ui.icons choice
+type iconSet struct {
+	nowPlaying, title, artist, album string
+	play, pause, stop                string
+	previous, next                   string
+	shuffle, loop, loopOne           string
+	volume, mute                     string
+}
+
+var iconSets = map[string]iconSet{
+	// Nerd Font (Material Design) glyphs: need a patched font
+	"nerd": {
+		nowPlaying: "󰓃", title: "󰎈", artist: "󰠃", album: "󰀥",
+		play: "󰐊", pause: "󰏤", stop: "󰓛",
+		previous: "󰒮", next: "󰒭",
+		shuffle: "󰒟", loop: "󰑖", loopOne: "󰑘",
+		volume: "󰕾", mute: "󰖁",
+	},
+	// Emoji, all double width. Only ones drawn as emoji by default: a
+	// glyph that needs a variation selector (⏸️, ⏮️) is one cell wide in
+	// some terminals and two in others. Nothing like that draws pause, so
+	// play and pause are the green and yellow lights.
+	"emoji": {
+		nowPlaying: "🎧", title: "🎵", artist: "🎤", album: "💿",
+		play: "🟢", pause: "🟡", stop: "🛑",
+		previous: "⏪", next: "⏩",
+		shuffle: "🔀", loop: "🔁", loopOne: "🔂",
+		volume: "🔊", mute: "🔇",
+	},
+	// Plain ASCII, for any font
+	"ascii": {
+		nowPlaying: ">>", title: "#", artist: "@", album: "o",
+		play: ">", pause: "||", stop: "[]",
+		previous: "|<", next: ">|",
+		shuffle: "%", loop: "<>", loopOne: "<1",
+		volume: "))", mute: "x",
+	},
+	// Text only
+	"none": {},
+}
+
+var iconSetNames = []string{"nerd", "emoji", "ascii", "none"}
+
+// iconsFor returns the ui.icons set (Nerd Font by default)
+func iconsFor(cfg Config) iconSet {
+	if set, ok := iconSets[cfg.UI.Icons]; ok {
+		return set
+	}
+	return iconSets["nerd"]
+}
+
+// status picks the icon for a play state (case-insensitive)
+func (s iconSet) status(status string) string {
+	switch strings.ToLower(status) {
+	case "paused":
+		return s.pause
+	case "stopped":
+		return s.stop
+	}
+	return s.play
+}
+
+// width is the label column's width: the widest of the line glyphs, so
+// text lines up whichever icon labels it
+func (s iconSet) width() int {
+	w := 0
+	for _, glyph := range []string{s.title, s.artist, s.album, s.play, s.pause, s.stop} {
+		w = max(w, lipgloss.Width(glyph))
+	}
+	return w
+}
+
+// labelWidth is the room a label takes before the text: the column plus
+// two spaces (none if the set has no icons)
+func (s iconSet) labelWidth() int {
+	if w := s.width(); w > 0 {
+		return w + 2
+	}
+	return 0
+}
+
+// label pads a glyph to the label column
+func (s iconSet) label(glyph string) string {
+	return glyph + strings.Repeat(" ", max(s.width()-lipgloss.Width(glyph), 0))
+}
+
+// withIcon puts an icon before text, or just the text if there's no icon
+func withIcon(icon, text string) string {
+	if icon == "" {
+		return text
+	}
+	return icon + " " + text
+}
+
+// names exposes the glyphs to ui.lines templates as .Icons
+func (s iconSet) names() map[string]string {
+	return map[string]string{
+		"now_playing": s.nowPlaying, "title": s.title, "artist": s.artist, "album": s.album,
+		"play": s.play, "pause": s.pause, "stop": s.stop,
+		"previous": s.previous, "next": s.next,
+		"shuffle": s.shuffle, "loop": s.loop, "loop_one": s.loopOne,
+		"volume": s.volume, "mute": s.mute,
+	}
+}
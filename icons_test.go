@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rivo/uniseg"
+)
+
+// TestIconSets verifies every set has every glyph and a set column width
+func TestIconSets(t *testing.T) {
+	for _, name := range iconSetNames {
+		s := iconSets[name]
+		for glyphName, glyph := range s.names() {
+			if name != "none" && glyph == "" {
+				t.Errorf("%s: missing %s", name, glyphName)
+			}
+			if name == "none" && glyph != "" {
+				t.Errorf("none: %s = %q", glyphName, glyph)
+			}
+		}
+
+		// Every status label pads to the same column
+		for _, status := range []string{"Playing", "Paused", "Stopped"} {
+			if got := lipgloss.Width(s.label(s.status(status))); got != s.width() {
+				t.Errorf("%s %s label width = %d, want %d", name, status, got, s.width())
+			}
+		}
+	}
+
+	assertEqual(t, iconSets["none"].labelWidth(), 0, "none label width")
+	assertEqual(t, iconSets["ascii"].status("paused"), "||", "ascii pause")
+}
+
+// TestViewIcons verifies the chosen set is what the view draws
+func TestViewIcons(t *testing.T) {
+	defer config.Set(config.Get())
+
+	m := model{width: 80, height: 30}
+	m.songData = SongData{Title: "Title", Artist: "Artist", Album: "Album", Status: "Paused"}
+
+	for _, name := range []string{"ascii", "none"} {
+		cfg := layoutTestConfig(layoutCard)
+		cfg.UI.Icons = name
+		config.Set(cfg)
+
+		view := m.View()
+		if strings.ContainsAny(view, iconSets["nerd"].title+iconSets["nerd"].pause) {
+			t.Errorf("%s: view has Nerd Font glyphs", name)
+		}
+		if !strings.Contains(view, "Title") {
+			t.Errorf("%s: view missing title", name)
+		}
+	}
+}
+
+// TestEmojiWidths verifies every emoji glyph is two cells wide without a
+// variation selector, whose width terminals disagree on
+func TestEmojiWidths(t *testing.T) {
+	for glyphName, glyph := range iconSets["emoji"].names() {
+		if strings.ContainsRune(glyph, '\uFE0F') {
+			t.Errorf("%s %q needs a variation selector", glyphName, glyph)
+		}
+		if w := uniseg.StringWidth(glyph); w != 2 {
+			t.Errorf("%s %q is %d cells wide", glyphName, glyph, w)
+		}
+	}
+}
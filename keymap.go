@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Actions keys can be bound to (the keys: config section)
+const (
+	actionPlayPause = "play_pause"
+	actionNext      = "next"
+	actionPrevious  = "previous"
+	actionArtwork   = "toggle_art"
+	actionAnimation = "animation"
+	actionTime      = "time"
+	actionCommand   = "command"
+	actionQuit      = "quit"
+	actionHelp      = "help"
+)
+
+// keyAction is a bindable action, in help order
+type keyAction struct {
+	name string
+	help string   // Label in the help line
+	keys []string // Default keys
+}
+
+var keyActions = []keyAction{
+	{actionPlayPause, "Play/Pause", []string{"p"}},
+	{actionNext, "Next", []string{"n"}},
+	{actionPrevious, "Previous", []string{"b"}},
+	{actionArtwork, "Toggle Art", []string{"a"}},
+	{actionAnimation, "Animation", []string{"v"}},
+	{actionTime, "Time", []string{"t"}},
+	{actionCommand, "Command", []string{":"}},
+	{actionQuit, "Quit", []string{"q"}},
+	{actionHelp, "Hide", []string{"?"}}, // Only listed while the help is shown
+}
+
+// namedKeys are the keys config can name besides single characters, as
+// Bubble Tea spells them (tea.KeyMsg.String)
+var namedKeys = map[string]bool{
+	"enter": true, "tab": true, "shift+tab": true, "esc": true, "backspace": true, "delete": true, "insert": true,
+	"up": true, "down": true, "left": true, "right": true, "home": true, "end": true, "pgup": true, "pgdown": true,
+	"shift+up": true, "shift+down": true, "shift+left": true, "shift+right": true,
+	"ctrl+up": true, "ctrl+down": true, "ctrl+left": true, "ctrl+right": true,
+}
+
+func init() {
+	for c := 'a'; c <= 'z'; c++ {
+		namedKeys["ctrl+"+string(c)] = true
+	}
+	for i := 1; i <= 20; i++ {
+		namedKeys[fmt.Sprintf("f%d", i)] = true
+	}
+}
+
+// normalizeKey turns a configured key into Bubble Tea's name for it:
+// "space" is " ", modifiers and named keys are lowercase ("Ctrl+C" is
+// "ctrl+c"), and single characters keep their case ("N" is shift+n).
+// ok is false for keys Bubble Tea doesn't have.
+func normalizeKey(key string) (string, bool) {
+	alt := false
+	if lower := strings.ToLower(key); strings.HasPrefix(lower, "alt+") && len(key) > len("alt+") {
+		alt, key = true, key[len("alt+"):]
+	}
+	switch {
+	case strings.EqualFold(key, "space") || key == " ":
+		key = " "
+	case utf8.RuneCountInString(key) == 1:
+	case namedKeys[strings.ToLower(key)]:
+		key = strings.ToLower(key)
+	default:
+		return "", false
+	}
+	if alt {
+		key = "alt+" + key
+	}
+	return key, true
+}
+
+// keyLabel is how help shows a key
+func keyLabel(key string) string {
+	return strings.ReplaceAll(key, " ", "space")
+}
+
+// keymap maps pressed keys to actions
+type keymap struct {
+	actions map[string]string   // Key → action
+	keys    map[string][]string // Action → keys, as configured
+}
+
+// buildKeymap puts the keys: section over the defaults: an action listed
+// there gets exactly those keys (none unbinds it). Unknown actions, keys
+// Bubble Tea doesn't have and keys bound twice are errors.
+func buildKeymap(configured map[string][]string) (keymap, error) {
+	km := keymap{actions: map[string]string{}, keys: map[string][]string{}}
+	for _, a := range keyActions {
+		km.keys[a.name] = a.keys
+	}
+
+	// Sorted, so errors come out the same every time
+	names := make([]string, 0, len(configured))
+	for name := range configured {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := km.keys[name]; !ok {
+			return keymap{}, fmt.Errorf("unknown action '%s'", name)
+		}
+		var keys []string
+		for _, key := range configured[name] {
+			normalized, ok := normalizeKey(key)
+			if !ok {
+				return keymap{}, fmt.Errorf("%s: unknown key '%s'", name, key)
+			}
+			keys = append(keys, normalized)
+		}
+		km.keys[name] = keys
+	}
+
+	for _, a := range keyActions {
+		for _, key := range km.keys[a.name] {
+			if other, ok := km.actions[key]; ok && other != a.name {
+				return keymap{}, fmt.Errorf("'%s' is bound to both %s and %s", keyLabel(key), other, a.name)
+			}
+			km.actions[key] = a.name
+		}
+	}
+	return km, nil
+}
+
+// keymapFor is the keymap for keys:, or the defaults if it's broken
+// (validateConfig reports it)
+func keymapFor(cfg Config) keymap {
+	km, err := buildKeymap(cfg.Keys)
+	if err != nil {
+		km, _ = buildKeymap(nil)
+	}
+	return km
+}
+
+// keyBindings is the keymap built on the last config change, or one built
+// now for a model without it (as in tests)
+func (m model) keyBindings(cfg Config) keymap {
+	if m.keys.actions != nil {
+		return m.keys
+	}
+	return keymapFor(cfg)
+}
+
+// action is what a key press does ("" for nothing)
+func (km keymap) action(key string) string {
+	return km.actions[key]
+}
+
+// help lists every bound action and its keys, e.g. "Next: n/right"
+func (km keymap) help(keyStyle lipgloss.Style) []string {
+	var items []string
+	for _, a := range keyActions {
+		keys := km.keys[a.name]
+		if len(keys) == 0 {
+			continue
+		}
+		labels := make([]string, len(keys))
+		for i, key := range keys {
+			labels[i] = keyLabel(key)
+		}
+		items = append(items, a.help+": "+keyStyle.Render(strings.Join(labels, "/")))
+	}
+	return items
+}
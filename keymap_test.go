@@ -0,0 +1,115 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TestNormalizeKey verifies config key names become Bubble Tea's
+func TestNormalizeKey(t *testing.T) {
+	tests := []struct {
+		key, want string
+		ok        bool
+	}{
+		{"p", "p", true},
+		{"N", "N", true},
+		{"space", " ", true},
+		{"Ctrl+C", "ctrl+c", true},
+		{"alt+n", "alt+n", true},
+		{"alt+space", "alt+ ", true},
+		{"Left", "left", true},
+		{"f5", "f5", true},
+		{"hyper+x", "", false},
+		{"ctrl+", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := normalizeKey(tt.key)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("normalizeKey(%q) = %q, %v; want %q, %v", tt.key, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// TestBuildKeymap verifies config keys replace an action's defaults, and
+// bad bindings are errors
+func TestBuildKeymap(t *testing.T) {
+	km, err := buildKeymap(map[string][]string{
+		"play_pause": {"p", "space"},
+		"next":       {"n", "right"},
+		"toggle_art": {},
+	})
+	assertNoError(t, err)
+	assertEqual(t, km.action(" "), actionPlayPause, "space")
+	assertEqual(t, km.action("right"), actionNext, "right arrow")
+	assertEqual(t, km.action("q"), actionQuit, "default kept")
+	assertEqual(t, km.action("a"), "", "unbound")
+
+	_, err = buildKeymap(map[string][]string{"play_pause": {"n"}})
+	assertError(t, err, "n bound to play_pause and next")
+	if err != nil && !strings.Contains(err.Error(), "play_pause and next") {
+		t.Errorf("conflict error doesn't name both actions: %v", err)
+	}
+	_, err = buildKeymap(map[string][]string{"dance": {"d"}})
+	assertError(t, err, "unknown action")
+	_, err = buildKeymap(map[string][]string{"next": {"hyper+n"}})
+	assertError(t, err, "unknown key")
+
+	// validateConfig reports it, and the defaults take over
+	cfg := Config{Keys: map[string][]string{"quit": {"p"}}}
+	var found bool
+	for _, e := range validateConfig(&cfg) {
+		if ce, ok := e.(configError); ok && ce.field == "keys" {
+			found = true
+		}
+	}
+	assertEqual(t, found, true, "conflict reported")
+	assertEqual(t, keymapFor(cfg).action("p"), actionPlayPause, "defaults after conflict")
+}
+
+// TestKeymapDispatch verifies Update and the help line follow the keymap
+func TestKeymapDispatch(t *testing.T) {
+	defer config.Set(config.Get())
+	cfg := layoutTestConfig(layoutCard)
+	cfg.Keys = map[string][]string{"quit": {"ctrl+c", "Q"}, "help": {"h"}}
+	config.Set(cfg)
+
+	m := model{width: 80, height: 30}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	assertEqual(t, updated.(model).quitting, false, "q unbound")
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	assertEqual(t, updated.(model).quitting, true, "ctrl+c quits")
+
+	if !strings.Contains(m.View(), "Press h for help") {
+		t.Error("help hint doesn't use the help key")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	help := strings.Join(strings.Fields(updated.(model).View()), " ")
+	for _, want := range []string{"Quit: ctrl+c/Q", "Hide: h", "Next: n"} {
+		if !strings.Contains(help, want) {
+			t.Errorf("help missing %q", want)
+		}
+	}
+
+	items := keymapFor(cfg).help(lipgloss.NewStyle())
+	assertEqual(t, len(items), len(keyActions), "every action in help")
+}
+
+// TestKeymapRebuiltOnReload verifies the model keeps its keymap until the
+// config changes
+func TestKeymapRebuiltOnReload(t *testing.T) {
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+	defer config.Set(config.Get())
+	cfg := layoutTestConfig(layoutCard)
+	m := model{width: 80, height: 30, keys: keymapFor(cfg)}
+
+	cfg.Keys = map[string][]string{"help": {"h"}}
+	config.Set(cfg)
+	assertEqual(t, m.keyBindings(cfg).action("h"), "", "old keymap until the reload")
+
+	updated, _ := m.Update(configReloadMsg{})
+	assertEqual(t, updated.(model).keyBindings(cfg).action("h"), actionHelp, "rebuilt on reload")
+}
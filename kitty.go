@@ -0,0 +1,258 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// Artwork transmission modes (artwork.transmission)
+	kittyTransmitDirect = "direct" // Base64 PNG data inline in the escape sequence (works over SSH)
+	kittyTransmitFile   = "file"   // Path to a PNG file the terminal reads itself (local sessions only)
+
+	// kittyPlacementID is the placement ID used for the artwork, so each
+	// placement command replaces the previous one instead of stacking
+	kittyPlacementID = 1
+
+	// kittyDeleteAll deletes every placement and frees all image data
+	kittyDeleteAll = "\033_Ga=d,d=A,q=2\033\\"
+	// kittyDeletePlacements removes every placement but keeps uploaded image
+	// data, so a following placement command redraws without re-transmitting
+	kittyDeletePlacements = "\033_Ga=d,d=a,q=2\033\\"
+)
+
+// isRemoteSession reports whether we're running over SSH, where the terminal
+// can't read our local files and only direct transmission works
+func isRemoteSession() bool {
+	return os.Getenv("SSH_CONNECTION") != "" || os.Getenv("SSH_TTY") != ""
+}
+
+// effectiveTransmission resolves the configured transmission mode to the one
+// that will actually work in this session
+func effectiveTransmission(mode string) string {
+	if mode == kittyTransmitFile && !isRemoteSession() {
+		return kittyTransmitFile
+	}
+	return kittyTransmitDirect
+}
+
+// kittyTransmit builds the command that uploads PNG data under the given image
+// ID without displaying it (a=t). Re-sending it replaces the image data, so
+// it's safe to emit more than once.
+// owner is who releases the temp file in file mode (see kittyTempFiles).
+func kittyTransmit(pngData []byte, imageID int, mode, owner string) (string, error) {
+	return kittyUpload(fmt.Sprintf("a=t,f=%d,i=%d", kittyFormatPNG, imageID), pngData, mode, owner)
+}
+
+// kittyUpload wraps PNG data in a graphics command with the given control keys,
+// either inline (chunked base64) or as a temp file path depending on mode
+func kittyUpload(control string, pngData []byte, mode, owner string) (string, error) {
+	if effectiveTransmission(mode) == kittyTransmitFile {
+		path, err := kittyFiles.write(pngData, owner)
+		if err != nil {
+			return "", err
+		}
+		encodedPath := base64.StdEncoding.EncodeToString([]byte(path))
+		return fmt.Sprintf("\033_G%s,t=f,q=2;%s\033\\", control, encodedPath), nil
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(pngData)
+
+	// Kitty protocol needs chunking for large payloads
+	var result strings.Builder
+	if len(encoded) <= kittyChunkSize {
+		// Small enough to send in one go
+		result.WriteString(fmt.Sprintf("\033_G%s,t=d,q=2;%s\033\\", control, encoded))
+		return result.String(), nil
+	}
+
+	for i := 0; i < len(encoded); i += kittyChunkSize {
+		end := i + kittyChunkSize
+		if end > len(encoded) {
+			end = len(encoded)
+		}
+		chunk := encoded[i:end]
+
+		if i == 0 {
+			// First chunk carries the control data
+			result.WriteString(fmt.Sprintf("\033_G%s,t=d,q=2,m=1;%s\033\\", control, chunk))
+		} else if end < len(encoded) {
+			// Middle chunk
+			result.WriteString(fmt.Sprintf("\033_Gm=1;%s\033\\", chunk))
+		} else {
+			// Last chunk
+			result.WriteString(fmt.Sprintf("\033_Gm=0;%s\033\\", chunk))
+		}
+	}
+	return result.String(), nil
+}
+
+// kittyAnimationUpload builds the upload for a terminal-side animation: the
+// first frame becomes the root image (a=t) and the rest are appended as
+// animation frames (a=f), each shown for gapMs before the next
+func kittyAnimationUpload(frames [][]byte, imageID int, gapMs int, mode, owner string) (string, error) {
+	if len(frames) == 0 {
+		return "", fmt.Errorf("no animation frames")
+	}
+	if gapMs < 1 {
+		gapMs = 1
+	}
+
+	var result strings.Builder
+	root, err := kittyTransmit(frames[0], imageID, mode, owner)
+	if err != nil {
+		return "", err
+	}
+	result.WriteString(root)
+
+	for _, frame := range frames[1:] {
+		cmd, err := kittyUpload(fmt.Sprintf("a=f,f=%d,i=%d,z=%d", kittyFormatPNG, imageID, gapMs), frame, mode, owner)
+		if err != nil {
+			return "", err
+		}
+		result.WriteString(cmd)
+	}
+
+	// The root frame's gap can only be set through an animation command
+	result.WriteString(fmt.Sprintf("\033_Ga=a,i=%d,r=1,z=%d,q=2\033\\", imageID, gapMs))
+	return result.String(), nil
+}
+
+// kittyAnimationControl starts (looping forever) or stops a terminal-side
+// animation. Idempotent, so View can emit it on every render.
+func kittyAnimationControl(imageID int, running bool) string {
+	if running {
+		return fmt.Sprintf("\033_Ga=a,i=%d,s=3,v=1,q=2\033\\", imageID)
+	}
+	return fmt.Sprintf("\033_Ga=a,i=%d,s=1,q=2\033\\", imageID)
+}
+
+// kittyPlace builds the small command that displays an already-uploaded image
+// at the cursor. Uses columns (c) instead of pixels for zoom-independent
+// sizing; height is auto-calculated to maintain aspect ratio. C=1 keeps the
+// cursor where it is so the text layout isn't disturbed.
+func kittyPlace(imageID int, columns int) string {
+	return fmt.Sprintf("\033_Ga=p,i=%d,p=%d,c=%d,C=1,q=2\033\\", imageID, kittyPlacementID, columns)
+}
+
+// kittyPlaceAbove is kittyPlace for overlays: z sets the stacking order, so
+// z=1 draws over the artwork placed at the same cursor position
+func kittyPlaceAbove(imageID int, columns int, z int) string {
+	return fmt.Sprintf("\033_Ga=p,i=%d,p=%d,c=%d,z=%d,C=1,q=2\033\\", imageID, kittyPlacementID, columns, z)
+}
+
+// kittyDeleteImage deletes one image's placements and frees its data
+func kittyDeleteImage(imageID int) string {
+	return fmt.Sprintf("\033_Ga=d,d=I,i=%d,q=2\033\\", imageID)
+}
+
+// Owners of file-mode temp files. Several writers run in the background,
+// so each one's files are released on their own: one can't remove a file
+// another is about to send.
+const (
+	kittyOwnerStill      = "still"      // The artwork (processArtworkCmd)
+	kittyOwnerTonearm    = "tonearm"    // The vinyl tonearm overlay
+	kittyOwnerTransition = "transition" // A cover transition's frames
+)
+
+// kittyOwnerAnimation owns one animation job's frames
+func kittyOwnerAnimation(job int) string {
+	return "animation " + strconv.Itoa(job)
+}
+
+// kittyTempFile is a file written for file-mode transmission, and who it
+// belongs to
+type kittyTempFile struct {
+	path, owner string
+}
+
+// kittyTempFiles tracks PNG files written for file-mode transmission so they
+// can be removed once superseded and on exit.
+type kittyTempFiles struct {
+	mu    sync.Mutex
+	dir   string
+	files []kittyTempFile
+	seq   int
+}
+
+var kittyFiles = &kittyTempFiles{}
+
+// write stores PNG data in a new temp file for owner and returns its path.
+// Prefers /dev/shm so the round trip never touches disk.
+func (k *kittyTempFiles) write(data []byte, owner string) (string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.dir == "" {
+		base := ""
+		if info, err := os.Stat("/dev/shm"); err == nil && info.IsDir() {
+			base = "/dev/shm"
+		}
+		dir, err := os.MkdirTemp(base, "goplaying-")
+		if err != nil {
+			return "", fmt.Errorf("failed to create artwork temp dir: %w", err)
+		}
+		k.dir = dir
+	}
+
+	k.seq++
+	path := filepath.Join(k.dir, fmt.Sprintf("artwork-%d.png", k.seq))
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write artwork temp file: %w", err)
+	}
+	k.files = append(k.files, kittyTempFile{path, owner})
+	return path, nil
+}
+
+// release removes owner's files, except any a command in keep sends (the
+// model may still re-send those, and the terminal reads the file then) and
+// any written after the newest kept one, which belong to work still in
+// flight. With nothing to keep, all of owner's files go.
+func (k *kittyTempFiles) release(owner string, keep ...string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	cutoff := len(k.files)
+	for i, f := range k.files {
+		if f.owner == owner && kittySends(keep, f.path) {
+			cutoff = i
+		}
+	}
+	kept := k.files[:0]
+	for i, f := range k.files {
+		if f.owner == owner && i < cutoff && !kittySends(keep, f.path) {
+			_ = os.Remove(f.path)
+			continue
+		}
+		kept = append(kept, f)
+	}
+	k.files = kept
+}
+
+// kittySends reports whether any of cmds transmits the file at path
+func kittySends(cmds []string, path string) bool {
+	encodedPath := base64.StdEncoding.EncodeToString([]byte(path))
+	for _, cmd := range cmds {
+		if strings.Contains(cmd, ";"+encodedPath+"\033\\") {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanup removes every temp file and the temp dir (called on exit)
+func (k *kittyTempFiles) cleanup() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.dir != "" {
+		_ = os.RemoveAll(k.dir)
+	}
+	k.dir = ""
+	k.files = nil
+}
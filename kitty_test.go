@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image/color"
+	"image/png"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestKittyTransmit tests the upload command for both transmission modes
+func TestKittyTransmit(t *testing.T) {
+	img := generateTestImage(20, 20, color.RGBA{200, 100, 50, 255})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("Failed to encode test image: %v", err)
+	}
+
+	t.Run("direct", func(t *testing.T) {
+		cmd, err := kittyTransmit(buf.Bytes(), kittyImageID, kittyTransmitDirect, kittyOwnerStill)
+		assertNoError(t, err)
+		if !strings.HasPrefix(cmd, "\033_Ga=t,") {
+			t.Errorf("Expected transmit-only command (a=t), got %q", cmd[:20])
+		}
+		if strings.Contains(cmd, "a=T") || strings.Contains(cmd, "a=p") {
+			t.Error("Transmit command must not display the image")
+		}
+	})
+
+	t.Run("direct chunks large payloads", func(t *testing.T) {
+		data := bytes.Repeat([]byte{0xAB}, kittyChunkSize*2)
+		cmd, err := kittyTransmit(data, kittyImageID, kittyTransmitDirect, kittyOwnerStill)
+		assertNoError(t, err)
+		if !strings.Contains(cmd, "m=1;") || !strings.Contains(cmd, "\033_Gm=0;") {
+			t.Error("Expected chunked transmission markers")
+		}
+	})
+
+	t.Run("file", func(t *testing.T) {
+		t.Setenv("SSH_CONNECTION", "")
+		t.Setenv("SSH_TTY", "")
+		defer kittyFiles.cleanup()
+
+		cmd, err := kittyTransmit(buf.Bytes(), kittyImageID, kittyTransmitFile, kittyOwnerStill)
+		assertNoError(t, err)
+		if !strings.Contains(cmd, "t=f") {
+			t.Fatalf("Expected file transmission, got %q", cmd)
+		}
+
+		// Payload is the base64-encoded path of a file holding the PNG
+		payload := cmd[strings.Index(cmd, ";")+1 : len(cmd)-2]
+		path, err := base64.StdEncoding.DecodeString(payload)
+		assertNoError(t, err)
+		data, err := os.ReadFile(string(path))
+		assertNoError(t, err)
+		if !bytes.Equal(data, buf.Bytes()) {
+			t.Error("Temp file content doesn't match PNG data")
+		}
+	})
+
+	t.Run("file falls back to direct over SSH", func(t *testing.T) {
+		t.Setenv("SSH_CONNECTION", "10.0.0.1 1234 10.0.0.2 22")
+		cmd, err := kittyTransmit(buf.Bytes(), kittyImageID, kittyTransmitFile, kittyOwnerStill)
+		assertNoError(t, err)
+		if !strings.Contains(cmd, "t=d") {
+			t.Error("Expected direct transmission in a remote session")
+		}
+	})
+}
+
+// TestKittyTempFilesRelease verifies each owner's superseded temp files are
+// removed without touching other owners' or ones still to be sent
+func TestKittyTempFilesRelease(t *testing.T) {
+	t.Setenv("SSH_CONNECTION", "")
+	t.Setenv("SSH_TTY", "")
+	saved := kittyFiles
+	kittyFiles = &kittyTempFiles{}
+	defer func() {
+		kittyFiles.cleanup()
+		kittyFiles = saved
+	}()
+
+	old, err := kittyFiles.write([]byte("old"), kittyOwnerStill)
+	assertNoError(t, err)
+	still, err := kittyTransmit([]byte("still"), kittyImageID, kittyTransmitFile, kittyOwnerStill)
+	assertNoError(t, err)
+	payload, err := base64.StdEncoding.DecodeString(still[strings.Index(still, ";")+1 : len(still)-2])
+	assertNoError(t, err)
+	// Written in the background after the still, before the model handles it
+	tonearm, err := kittyFiles.write([]byte("tonearm"), kittyOwnerTonearm)
+	assertNoError(t, err)
+	pending, err := kittyFiles.write([]byte("pending"), kittyOwnerStill)
+	assertNoError(t, err)
+
+	kittyFiles.release(kittyOwnerStill, still)
+
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Error("Expected superseded still to be removed")
+	}
+	for _, path := range []string{string(payload), tonearm, pending} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s to be kept: %v", path, err)
+		}
+	}
+
+	kittyFiles.release(kittyOwnerTonearm)
+	if _, err := os.Stat(tonearm); !os.IsNotExist(err) {
+		t.Error("Expected released tonearm to be removed")
+	}
+}
+
+// TestAnimationJobPrunesTempFiles verifies a new frame job removes the
+// previous job's temp files, and only those
+func TestAnimationJobPrunesTempFiles(t *testing.T) {
+	saved := kittyFiles
+	kittyFiles = &kittyTempFiles{}
+	defer func() {
+		kittyFiles.cleanup()
+		kittyFiles = saved
+	}()
+
+	m := model{lastTrackID: "t", rawArtworkData: []byte("raw"), animJob: 3}
+	var frames []string
+	for _, data := range []string{"one", "two"} {
+		path, err := kittyFiles.write([]byte(data), kittyOwnerAnimation(m.animJob))
+		assertNoError(t, err)
+		frames = append(frames, path)
+	}
+	still, err := kittyFiles.write([]byte("still"), kittyOwnerStill)
+	assertNoError(t, err)
+
+	cfg := layoutTestConfig(layoutCard)
+	cfg.Artwork.Animation = "vinyl"
+	cfg.Artwork.VinylFrames = 36
+	if m.startAnimationFrames(cfg) == nil {
+		t.Fatal("no frame job started")
+	}
+	m.animCancel()
+
+	for _, path := range frames {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("stale frame file %s kept", path)
+		}
+	}
+	if _, err := os.Stat(still); err != nil {
+		t.Errorf("still removed: %v", err)
+	}
+}
+
+// TestViewUploadsArtworkOnce verifies the artwork payload is only sent while
+// an upload is pending, and later renders just re-place the image
+func TestViewUploadsArtworkOnce(t *testing.T) {
+	cfg := Config{}
+	cfg.UI.MaxWidth = 45
+	cfg.Artwork.Enabled = true
+	cfg.Artwork.Padding = 16
+	cfg.Artwork.WidthColumns = 14
+	config.Set(cfg)
+
+	payload := "\033_Ga=t,f=100,t=d,i=42,q=2;" + strings.Repeat("A", 100000) + "\033\\"
+	m := model{supportsKitty: true, width: 80, height: 30}
+	m.songData.Title = "Song"
+	m.showArtwork(payload)
+
+	if !strings.Contains(m.View(), payload) {
+		t.Fatal("Expected pending upload to be included in the render")
+	}
+
+	updated, _ := m.Update(artworkUploadedMsg{seq: m.artworkUploadSeq})
+	m = updated.(model)
+
+	view := m.View()
+	if strings.Contains(view, payload) {
+		t.Error("Expected artwork payload to be omitted once uploaded")
+	}
+	if !strings.Contains(view, kittyPlace(kittyImageID, cfg.Artwork.WidthColumns)) {
+		t.Error("Expected placement command on every render")
+	}
+	if len(view) > 10000 {
+		t.Errorf("Render is %d bytes, expected only text and a placement", len(view))
+	}
+
+	t.Run("stale ack keeps newer upload pending", func(t *testing.T) {
+		m.showArtwork(payload)
+		updated, _ := m.Update(artworkUploadedMsg{seq: m.artworkUploadSeq - 1})
+		if !updated.(model).artworkUploadPending {
+			t.Error("Stale ack cleared a newer pending upload")
+		}
+	})
+}
+
+// BenchmarkViewArtworkBytes measures bytes written per render with artwork
+// shown, which is what reaches the terminal on every UI tick
+func BenchmarkViewArtworkBytes(b *testing.B) {
+	cfg := Config{}
+	cfg.UI.MaxWidth = 45
+	cfg.Artwork.Enabled = true
+	cfg.Artwork.Padding = 16
+	cfg.Artwork.WidthPixels = 300
+	cfg.Artwork.WidthColumns = 14
+	config.Set(cfg)
+
+	img := generateGradientImage(300, 300, color.RGBA{200, 40, 90, 255}, color.RGBA{20, 120, 220, 255})
+	encoded, err := encodeArtworkForKitty(img, cfg, 0, 0, 90)
+	if err != nil {
+		b.Fatalf("Failed to encode artwork: %v", err)
+	}
+
+	m := model{supportsKitty: true, width: 80, height: 30}
+	m.songData.Title = "Song"
+	m.showArtwork(encoded)
+	m.artworkUploadPending = false
+
+	var total int
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		total += len(m.View())
+	}
+	b.ReportMetric(float64(total)/float64(b.N), "bytes/render")
+}
+
+// TestKittyAnimationUpload tests packing frames into a terminal-side animation
+func TestKittyAnimationUpload(t *testing.T) {
+	frames := [][]byte{[]byte("frame0"), []byte("frame1"), []byte("frame2")}
+
+	upload, err := kittyAnimationUpload(frames, kittyImageID, 66, kittyTransmitDirect, kittyOwnerAnimation(1))
+	assertNoError(t, err)
+
+	if !strings.HasPrefix(upload, "\033_Ga=t,") {
+		t.Error("Expected first frame to be uploaded as the root image (a=t)")
+	}
+	if got := strings.Count(upload, "a=f,"); got != len(frames)-1 {
+		t.Errorf("Expected %d animation frames (a=f), got %d", len(frames)-1, got)
+	}
+	if !strings.Contains(upload, "z=66") {
+		t.Error("Expected frame gap in the upload")
+	}
+	if !strings.Contains(upload, "a=a,i=42,r=1,z=66") {
+		t.Error("Expected root frame gap to be set via an animation command")
+	}
+
+	t.Run("no frames", func(t *testing.T) {
+		_, err := kittyAnimationUpload(nil, kittyImageID, 66, kittyTransmitDirect, kittyOwnerAnimation(1))
+		assertError(t, err, "empty frame list")
+	})
+}
+
+// TestKittyAnimationControl tests starting and stopping terminal-side animation
+func TestKittyAnimationControl(t *testing.T) {
+	if got := kittyAnimationControl(kittyImageID, true); !strings.Contains(got, "s=3") || !strings.Contains(got, "v=1") {
+		t.Errorf("Expected looping run command, got %q", got)
+	}
+	if got := kittyAnimationControl(kittyImageID, false); !strings.Contains(got, "s=1") {
+		t.Errorf("Expected stop command, got %q", got)
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import "github.com/charmbracelet/lipgloss"
+
+// ui.layout values
+const (
+	layoutCard       = "card"       // Artwork left of the text in a fixed-width box (ui.max_width)
+	layoutVertical   = "vertical"   // Artwork on top, text centered below
+	layoutCompact    = "compact"    // No artwork or heading, for small terminals
+	layoutFullscreen = "fullscreen" // Vertical, scaled to fill the terminal
+	layoutMini       = "mini"       // One line, for tmux splits and status bars
+	layoutAuto       = "auto"       // Picked from the terminal size
+)
+
+var layoutNames = []string{layoutCard, layoutVertical, layoutCompact, layoutFullscreen, layoutMini, layoutAuto}
+
+const (
+	// progressTimeWidth is the room beside the progress bar for " 1:23/4:56"
+	progressTimeWidth = 13
+	// textRows is how many rows the text takes with its heading, progress
+	// bar and the help line under the box
+	textRows = 10
+	// boxRows is the box's top and bottom border and padding
+	boxRows = 4
+	// minArtColumns is the smallest cover worth showing
+	minArtColumns = 6
+	// miniMaxHeight is the terminal height below which every layout
+	// becomes mini: nothing with a border fits
+	miniMaxHeight = 8
+	// miniBarWidth is mini's progress bar, shown when there's room
+	miniBarWidth = 10
+)
+
+// layout is the geometry View renders with
+type layout struct {
+	name       string
+	boxWidth   int // Box width including padding, excluding the border
+	padX, padY int // Box padding
+	artColumns int // Artwork width in terminal columns (0 = no artwork)
+	artRows    int // Rows kept free above the text for the artwork (vertical layouts)
+	textIndent int // Columns left of the text for the artwork (card)
+	textWidth  int // Longest text shown before it scrolls
+	barWidth   int // Progress bar width
+	heading    bool
+	controls   bool // Clickable transport buttons under the box
+}
+
+// innerWidth is the box's content width
+func (l layout) innerWidth() int {
+	return l.boxWidth - 2*l.padX
+}
+
+// autoLayout picks a layout for the terminal size (in cells, which are
+// about twice as tall as they are wide)
+func autoLayout(cfg Config, width, height int) string {
+	switch {
+	case width == 0 || height == 0:
+		return layoutCard // No WindowSizeMsg yet
+	case width < cfg.UI.MaxWidth+2 || height < 14:
+		return layoutCompact
+	case width >= 120 && height >= 40:
+		return layoutFullscreen
+	case height >= 30 && width < 2*height:
+		return layoutVertical
+	}
+	return layoutCard
+}
+
+// computeLayout works out the geometry for ui.layout at the terminal size.
+// showArt is whether artwork can be shown at all (Kitty support, enabled).
+func computeLayout(cfg Config, width, height int, showArt bool) layout {
+	name := cfg.UI.Layout
+	switch name {
+	case "":
+		name = layoutCard
+	case layoutAuto:
+		name = autoLayout(cfg, width, height)
+	}
+	if height > 0 && height < miniMaxHeight {
+		name = layoutMini
+	}
+	if name == layoutFullscreen && (width == 0 || height == 0) {
+		name = layoutVertical // Can't fill a terminal of unknown size
+	}
+
+	l := layout{name: name, boxWidth: cfg.UI.MaxWidth, padX: 2, padY: 1, heading: true, controls: mouseEnabled(cfg)}
+	rows := textRows
+	if l.controls {
+		rows++
+	}
+	switch name {
+	case layoutCard:
+		if showArt {
+			l.artColumns = cfg.Artwork.WidthColumns
+			l.textIndent = cfg.Artwork.Padding
+		}
+	case layoutVertical, layoutFullscreen:
+		if name == layoutFullscreen {
+			l.boxWidth = width - 2 // Border
+		}
+		if showArt {
+			// The cover has the box's width to itself: twice the card size,
+			// or as large as the terminal allows in fullscreen
+			l.artColumns = min(2*cfg.Artwork.WidthColumns, l.innerWidth())
+			if name == layoutFullscreen {
+				l.artColumns = l.innerWidth()
+			}
+			if height > 0 {
+				// A square cover takes half as many rows as columns, plus a
+				// blank row before the text
+				l.artColumns = min(l.artColumns, 2*(height-rows-boxRows-1))
+			}
+			if l.artColumns < minArtColumns {
+				l.artColumns = 0
+			}
+			if l.artColumns > 0 {
+				l.artRows = (l.artColumns+1)/2 + 1
+			}
+		}
+	case layoutCompact:
+		if width > 0 {
+			l.boxWidth = max(min(cfg.UI.MaxWidth, width-2), 20)
+		}
+		l.padX, l.padY = 1, 0
+		l.heading = false
+	case layoutMini:
+		// No box: the line is the terminal's width. Room goes to the
+		// status icon, the text, the bar (if the text keeps 20 columns)
+		// and the time.
+		if width > 0 {
+			l.boxWidth = width
+		}
+		l.padX, l.padY = 0, 0
+		l.heading = false
+		l.controls = false
+		l.barWidth = 0
+		icon := 0
+		if w := iconsFor(cfg).width(); w > 0 {
+			icon = w + 1
+		}
+		if l.boxWidth-icon-progressTimeWidth-miniBarWidth-1 >= 20 {
+			l.barWidth = miniBarWidth
+		}
+		l.textWidth = max(l.boxWidth-icon-progressTimeWidth-l.barWidth-1, 1)
+		return l
+	}
+
+	l.textWidth = max(l.innerWidth()-l.textIndent-iconsFor(cfg).labelWidth(), 1)
+	l.barWidth = max(l.innerWidth()-progressTimeWidth, 0)
+	return l
+}
+
+// layout is computeLayout for the model's terminal size, or for the rows
+// inline mode may take
+func (m model) layout(cfg Config) layout {
+	height := m.height
+	if cfg.UI.Inline && height > 0 {
+		height = min(height, cfg.UI.InlineHeight)
+	}
+	return computeLayout(cfg, m.width, height, m.supportsKitty && cfg.Artwork.Enabled)
+}
+
+// place positions the rendered UI: centered on the alternate screen, or
+// as is inline, where it takes only the rows it needs
+func (m model) place(cfg Config, ui string, hPos lipgloss.Position) string {
+	if cfg.UI.Inline {
+		return ui
+	}
+	return lipgloss.Place(m.width, m.height, hPos, lipgloss.Center, ui)
+}
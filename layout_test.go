@@ -0,0 +1,135 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func layoutTestConfig(name string) Config {
+	cfg := Config{}
+	cfg.UI.MaxWidth = 45
+	cfg.UI.Layout = name
+	cfg.Artwork.Enabled = true
+	cfg.Artwork.Padding = 16
+	cfg.Artwork.WidthColumns = 14
+	cfg.Text.Overflow = overflowMarquee
+	cfg.Text.ScrollSpeed = 3
+	cfg.Text.ScrollPauseMs = 3000
+	return cfg
+}
+
+// TestComputeLayout verifies each layout's geometry
+func TestComputeLayout(t *testing.T) {
+	// Card keeps the classic sizes: 22 columns of text beside the artwork
+	card := computeLayout(layoutTestConfig(layoutCard), 80, 30, true)
+	assertEqual(t, card.artColumns, 14, "card artwork")
+	assertEqual(t, card.textWidth, 22, "card text beside artwork")
+	assertEqual(t, card.barWidth, 28, "card progress bar")
+	noArt := computeLayout(layoutTestConfig(layoutCard), 80, 30, false)
+	assertEqual(t, noArt.artColumns, 0, "card without artwork")
+	assertEqual(t, noArt.textWidth, 38, "card text without artwork")
+
+	// Vertical: the cover on top gets the width, the text all of it too
+	vertical := computeLayout(layoutTestConfig(layoutVertical), 80, 60, true)
+	assertEqual(t, vertical.artColumns, 28, "vertical artwork")
+	assertEqual(t, vertical.artRows, 15, "vertical artwork rows")
+	assertEqual(t, vertical.textWidth, 38, "vertical text")
+	short := computeLayout(layoutTestConfig(layoutVertical), 80, 26, true)
+	if short.artRows+textRows+boxRows > 26 {
+		t.Errorf("vertical artwork (%d rows) overflows a 26-row terminal", short.artRows)
+	}
+	tiny := computeLayout(layoutTestConfig(layoutVertical), 80, 16, true)
+	assertEqual(t, tiny.artColumns, 0, "vertical artwork with no room")
+
+	// Fullscreen grows with the terminal
+	small := computeLayout(layoutTestConfig(layoutFullscreen), 120, 40, true)
+	large := computeLayout(layoutTestConfig(layoutFullscreen), 200, 60, true)
+	assertEqual(t, large.boxWidth, 198, "fullscreen box")
+	if large.artColumns <= small.artColumns || large.textWidth <= small.textWidth {
+		t.Errorf("fullscreen didn't scale: %+v vs %+v", small, large)
+	}
+
+	compact := computeLayout(layoutTestConfig(layoutCompact), 30, 10, true)
+	assertEqual(t, compact.artColumns, 0, "compact artwork")
+	assertEqual(t, compact.boxWidth, 28, "compact box")
+	assertEqual(t, compact.heading, false, "compact heading")
+}
+
+// TestAutoLayout verifies auto picks a layout from the terminal size
+func TestAutoLayout(t *testing.T) {
+	cfg := layoutTestConfig(layoutAuto)
+	tests := []struct {
+		width, height int
+		want          string
+	}{
+		{0, 0, layoutCard},
+		{80, 24, layoutCard},
+		{40, 24, layoutCompact},
+		{80, 10, layoutCompact},
+		{60, 40, layoutVertical},
+		{200, 60, layoutFullscreen},
+	}
+
+	for _, tt := range tests {
+		if got := computeLayout(cfg, tt.width, tt.height, true).name; got != tt.want {
+			t.Errorf("%dx%d: got %s, want %s", tt.width, tt.height, got, tt.want)
+		}
+	}
+}
+
+// TestViewLayouts verifies each layout places (or hides) the artwork
+func TestViewLayouts(t *testing.T) {
+	defer config.Set(config.Get())
+
+	for _, name := range []string{layoutCard, layoutVertical, layoutFullscreen, layoutCompact} {
+		t.Run(name, func(t *testing.T) {
+			cfg := layoutTestConfig(name)
+			config.Set(cfg)
+			m := model{supportsKitty: true, width: 100, height: 50, artworkEncoded: "art"}
+			m.songData.Title = "Song"
+			m.songData.Artist = "Artist"
+
+			lay := m.layout(cfg)
+			view := m.View()
+			if lay.artColumns > 0 && !strings.Contains(view, kittyPlace(kittyImageID, lay.artColumns)) {
+				t.Errorf("expected the artwork placed %d columns wide", lay.artColumns)
+			}
+			if lay.artColumns == 0 && !strings.Contains(view, kittyDeletePlacements) {
+				t.Error("expected the artwork hidden")
+			}
+			if strings.Contains(view, "Now Playing") != lay.heading {
+				t.Errorf("heading shown: %v, want %v", !lay.heading, lay.heading)
+			}
+		})
+	}
+}
+
+// TestInlineView verifies inline mode renders only the rows it needs, within
+// ui.inline_height, and clears itself on quit
+func TestInlineView(t *testing.T) {
+	defer config.Set(config.Get())
+	cfg := layoutTestConfig(layoutFullscreen)
+	cfg.UI.Inline = true
+	cfg.UI.InlineHeight = 20
+	config.Set(cfg)
+
+	m := model{width: 120, height: 60}
+	m.songData = SongData{Title: "Song", Artist: "Artist", Status: "Playing"}
+	if rows := strings.Count(m.View(), "\n") + 1; rows > cfg.UI.InlineHeight {
+		t.Errorf("inline view takes %d rows, want <= %d", rows, cfg.UI.InlineHeight)
+	}
+	if got := m.layout(cfg).name; got != layoutFullscreen {
+		t.Errorf("inline layout = %s, want fullscreen", got)
+	}
+
+	cfg.UI.InlineHeight = 3
+	config.Set(cfg)
+	assertEqual(t, m.layout(cfg).name, layoutMini, "inline layout in 3 rows")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if view := updated.(model).View(); view != "" {
+		t.Errorf("view after quit = %q, want empty", view)
+	}
+}
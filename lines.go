@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// lineConfig is one ui.lines entry: an icon label and the text after it,
+// both text/template strings over lineData
+type lineConfig struct {
+	Label string `mapstructure:"label"`
+	Text  string `mapstructure:"text"`
+}
+
+// defaultLines are the title, artist, album and status lines
+var defaultLines = []lineConfig{
+	{Label: "{{.Icons.title}}", Text: "{{.Title}}"},
+	{Label: "{{.Icons.artist}}", Text: "{{.Artist}}"},
+	{Label: "{{.Icons.album}}", Text: "{{.Album}}"},
+	{Label: "{{.StatusIcon}}", Text: "{{.Status}}"},
+}
+
+// lineData is what line templates see
+type lineData struct {
+	Title, Artist, Album, Status string
+	StatusIcon                   string            // Play, pause or stop icon for Status (ui.icons)
+	Icons                        map[string]string // Every ui.icons glyph by name, e.g. .Icons.album
+	Year                         string            // Release year, if the player reports it
+	Position, Length             int64             // Seconds (use the duration helper)
+}
+
+// lineFuncs are the helpers line templates can call
+var lineFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	// truncate shortens s to n cells, ending in "…"
+	"truncate": func(n int, s string) string {
+		return truncateText(s, n)
+	},
+	// duration formats seconds like the progress bar's timestamps
+	"duration": func(seconds int64) string {
+		return formatTime(seconds)
+	},
+	// default is s, or fallback when s is empty
+	"default": func(fallback, s string) string {
+		if s == "" {
+			return fallback
+		}
+		return s
+	},
+}
+
+// sampleLineData checks templates run, not just parse
+var sampleLineData = lineData{
+	Title: "Title", Artist: "Artist", Album: "Album", Status: "Playing",
+	StatusIcon: iconSets["nerd"].play, Icons: iconSets["nerd"].names(), Year: "1999", Position: 65, Length: 200,
+}
+
+// compiledLine is a parsed ui.lines entry
+type compiledLine struct {
+	label, text *template.Template
+}
+
+// compileLines parses ui.lines and test-runs it against sample data
+func compileLines(lines []lineConfig) ([]compiledLine, error) {
+	compiled := make([]compiledLine, 0, len(lines))
+	for i, line := range lines {
+		label, err := template.New("label").Funcs(lineFuncs).Option("missingkey=error").Parse(line.Label)
+		if err != nil {
+			return nil, fmt.Errorf("line %d label: %w", i+1, err)
+		}
+		text, err := template.New("text").Funcs(lineFuncs).Option("missingkey=error").Parse(line.Text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d text: %w", i+1, err)
+		}
+		c := compiledLine{label, text}
+		if _, _, err := c.render(sampleLineData); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		compiled = append(compiled, c)
+	}
+	return compiled, nil
+}
+
+func (c compiledLine) render(data lineData) (label, text string, err error) {
+	var b strings.Builder
+	if err := c.label.Execute(&b, data); err != nil {
+		return "", "", err
+	}
+	label = b.String()
+	b.Reset()
+	if err := c.text.Execute(&b, data); err != nil {
+		return "", "", err
+	}
+	// Lines are single lines: a template's newlines would break the layout,
+	// so the ones around it go and any inside become spaces. The track's
+	// own spacing is kept.
+	text = strings.Trim(b.String(), "\r\n")
+	text = strings.NewReplacer("\r\n", " ", "\n", " ").Replace(text)
+	return strings.TrimSpace(label), text, nil
+}
+
+// lineCache keeps the parsed ui.lines between renders
+var lineCache struct {
+	mu       sync.Mutex
+	key      string
+	compiled []compiledLine
+}
+
+// linesFor returns the parsed ui.lines, or the defaults when it's empty or
+// broken (validateConfig reports it; rendering never fails)
+func linesFor(cfg Config) []compiledLine {
+	lines := cfg.UI.Lines
+	if len(lines) == 0 {
+		lines = defaultLines
+	}
+	key := fmt.Sprintf("%q", lines)
+
+	lineCache.mu.Lock()
+	defer lineCache.mu.Unlock()
+	if lineCache.compiled != nil && lineCache.key == key {
+		return lineCache.compiled
+	}
+	compiled, err := compileLines(lines)
+	if err != nil {
+		compiled, _ = compileLines(defaultLines)
+	}
+	lineCache.key, lineCache.compiled = key, compiled
+	return compiled
+}
+
+// renderedLine is one text line, before scrolling
+type renderedLine struct {
+	label, text string
+}
+
+// renderLines runs ui.lines over the current track. Lines that come out
+// empty are left out, and a line that fails to run is replaced by the
+// defaults as a whole.
+func (m model) renderLines(cfg Config) []renderedLine {
+	data := lineData{
+		Title:      m.songData.Title,
+		Artist:     m.songData.Artist,
+		Album:      m.songData.Album,
+		Status:     m.songData.Status,
+		StatusIcon: iconsFor(cfg).status(m.songData.Status),
+		Icons:      iconsFor(cfg).names(),
+		Year:       m.songData.Year,
+		Position:   int64(m.getCurrentPosition()),
+		Length:     m.duration,
+	}
+
+	render := func(lines []compiledLine) ([]renderedLine, error) {
+		var rendered []renderedLine
+		for _, line := range lines {
+			label, text, err := line.render(data)
+			if err != nil {
+				return nil, err
+			}
+			if strings.TrimSpace(text) != "" {
+				rendered = append(rendered, renderedLine{label, text})
+			}
+		}
+		return rendered, nil
+	}
+
+	rendered, err := render(linesFor(cfg))
+	if err != nil {
+		defaults, _ := compileLines(defaultLines)
+		rendered, _ = render(defaults)
+	}
+	return rendered
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestCompileLines verifies templates are parsed and test-run
+func TestCompileLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    lineConfig
+		wantErr bool
+	}{
+		{"fields", lineConfig{"󰎈", "{{.Artist}} · {{.Year}}"}, false},
+		{"conditional", lineConfig{"", "{{if .Album}}on {{.Album}}{{end}}"}, false},
+		{"helpers", lineConfig{"{{.StatusIcon}}", `{{upper .Title}} {{truncate 5 .Album}} {{duration .Length}} {{default "?" .Year}}`}, false},
+		{"syntax error", lineConfig{"", "{{.Title"}, true},
+		{"unknown field", lineConfig{"", "{{.Genre}}"}, true},
+		{"unknown function", lineConfig{"", "{{shout .Title}}"}, true},
+		{"broken label", lineConfig{"{{", "{{.Title}}"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := compileLines([]lineConfig{tt.line})
+			if tt.wantErr {
+				assertError(t, err, tt.name)
+			} else {
+				assertNoError(t, err)
+			}
+		})
+	}
+
+	cfg := Config{}
+	cfg.UI.Lines = []lineConfig{{Text: "{{.Title"}}
+	found := false
+	for _, err := range validateConfig(&cfg) {
+		if configErr, ok := err.(configError); ok && configErr.field == "ui.lines" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("validateConfig should report a broken ui.lines")
+	}
+}
+
+// TestLineHelpers verifies the template helper functions
+func TestLineHelpers(t *testing.T) {
+	truncate := lineFuncs["truncate"].(func(int, string) string)
+	assertEqual(t, truncate(5, "Abbey Road"), "Abbe…", "truncate")
+	assertEqual(t, truncate(20, "Abbey Road"), "Abbey Road", "truncate short text")
+	assertEqual(t, lineFuncs["duration"].(func(int64) string)(200), "03:20", "duration")
+	assertEqual(t, lineFuncs["default"].(func(string, string) string)("?", ""), "?", "default")
+}
+
+// TestRenderLines verifies lines render over the track, empty lines are
+// dropped, and a line failing at render time falls back to the defaults
+func TestRenderLines(t *testing.T) {
+	m := model{duration: 200}
+	m.songData = SongData{Title: "Song", Artist: "Artist", Status: "Paused", Year: "1969"}
+
+	cfg := Config{}
+	cfg.UI.Lines = []lineConfig{
+		{Label: "{{.StatusIcon}}", Text: "{{.Artist}} · {{.Year}}"},
+		{Label: "󰀥", Text: "{{.Album}}"}, // No album: dropped
+		{Label: "", Text: "{{upper .Title}}"},
+	}
+	lines := m.renderLines(cfg)
+	assertEqual(t, len(lines), 2, "rendered lines")
+	assertEqual(t, lines[0], renderedLine{iconSets["nerd"].pause, "Artist · 1969"}, "first line")
+	assertEqual(t, lines[1].text, "SONG", "helper line")
+
+	// Newlines go, spacing stays
+	m.songData.Title = "Song  (Live)"
+	cfg.UI.Lines = []lineConfig{{Text: "{{.Title}}\n"}, {Text: " {{.Album}}\n"}, {Text: "{{.Title}}\n{{.Artist}}"}}
+	lines = m.renderLines(cfg)
+	assertEqual(t, len(lines), 2, "blank line dropped")
+	assertEqual(t, lines[0].text, "Song  (Live)", "trailing newline")
+	assertEqual(t, lines[1].text, "Song  (Live) Artist", "newline inside")
+	m.songData.Title = "Song"
+
+	// Valid against the sample data, but fails without a year
+	m.songData.Year = ""
+	cfg.UI.Lines = []lineConfig{{Text: "{{if .Year}}{{.Year}}{{else}}{{.Nope}}{{end}}"}}
+	lines = m.renderLines(cfg)
+	assertEqual(t, lines[0].text, "Song", "fallback to default lines")
+
+	// Every line scrolls on its own in the view
+	defer config.Set(config.Get())
+	cfg = layoutTestConfig(layoutCard)
+	cfg.UI.Lines = []lineConfig{{Label: "#", Text: "{{.Title}} by {{.Artist}}"}}
+	config.Set(cfg)
+	if view := m.View(); !strings.Contains(view, "Song by Artist") || strings.Contains(view, "󰠃") {
+		t.Error("view should show only the configured line")
+	}
+}
@@ -18,6 +18,8 @@ var (
 
 var colorFlag string
 var noArtworkFlag bool
+var miniFlag bool
+var inlineFlag bool
 var versionFlag bool
 var cpuProfile string
 var memProfile string
@@ -26,6 +28,8 @@ func init() {
 	flag.StringVar(&colorFlag, "color", "2", "Set the desired color (name or hex)")
 	flag.StringVar(&colorFlag, "c", "2", "Set the desired color (shorthand)")
 	flag.BoolVar(&noArtworkFlag, "no-artwork", false, "Disable album artwork display")
+	flag.BoolVar(&miniFlag, "mini", false, "Single-line display (same as ui.layout: mini)")
+	flag.BoolVar(&inlineFlag, "inline", false, "Render in place in the shell instead of taking over the screen")
 	flag.BoolVar(&versionFlag, "version", false, "Print version and exit")
 	flag.StringVar(&cpuProfile, "cpuprofile", "", "Write CPU profile to file")
 	flag.StringVar(&memProfile, "memprofile", "", "Write memory profile to file")
@@ -66,18 +70,45 @@ func main() {
 
 	// Start with manual color, auto mode will override when artwork loads
 	cfg := config.Get()
+
+	// Ask the terminal for its background before Bubble Tea takes over input
+	if cfg.UI.Background == backgroundAuto {
+		detectTerminalBackground()
+	}
+	detectedColorProfile = detectColorProfile()
+	applyColorProfile(cfg)
 	initialColor := cfg.UI.Color
 
 	initialModel := model{
-		color:           initialColor,
-		mediaController: NewMediaController(),
+		color:             initialColor,
+		backgroundSetting: cfg.UI.Background,
+		mediaController:   NewMediaController(),
+		hits:              &hitMap{},
+		keys:              keymapFor(cfg),
 		// Terminal capability only — whether artwork is shown is a config
 		// decision checked at render/fetch time, so toggling artwork on at
 		// runtime works even when it was disabled at startup
-		supportsKitty: supportsKittyGraphics(),
+		supportsKitty:     supportsKittyGraphics(),
+		supportsKittyAnim: supportsKittyAnimation(),
 	}
 
-	if _, err := tea.NewProgram(initialModel, tea.WithAltScreen()).Run(); err != nil {
+	// Inline mode renders in the normal scrollback, below the prompt
+	var options []tea.ProgramOption
+	if !cfg.UI.Inline {
+		options = append(options, tea.WithAltScreen())
+	}
+	// All motion, not just drags, so hovering the bar shows its time
+	if mouseEnabled(cfg) {
+		options = append(options, tea.WithMouseAllMotion())
+	}
+	_, err := tea.NewProgram(initialModel, options...).Run()
+	// Inline, the artwork would outlive the program in the scrollback
+	if cfg.UI.Inline && initialModel.supportsKitty {
+		fmt.Print(kittyDeleteAll)
+	}
+	// Remove any artwork temp files (file transmission mode)
+	kittyFiles.cleanup()
+	if err != nil {
 		fmt.Printf("Error: %v", err)
 		os.Exit(1)
 	}
@@ -6,6 +6,11 @@ import "errors"
 // as the friendly idle state rather than an error.
 var ErrNothingPlaying = errors.New("nothing playing")
 
+// ErrUnsupported is what Seek and the volume calls return when the player
+// can't do it (an MPRIS player without CanSeek, say). The UI treats it as
+// a no-op rather than a failure.
+var ErrUnsupported = errors.New("not supported by this player")
+
 // MediaController defines the interface for controlling media playback across platforms
 type MediaController interface {
 	GetMetadata() (title, artist, album, status string, err error)
@@ -15,3 +20,38 @@ type MediaController interface {
 	// GetArtwork returns raw image bytes (PNG/JPEG/etc), not base64
 	GetArtwork() ([]byte, error)
 }
+
+// YearProvider is implemented by controllers that know the track's release
+// year (for ui.lines templates). Call after GetMetadata.
+type YearProvider interface {
+	GetYear() string
+}
+
+// SourceReporter is implemented by controllers that can say where metadata
+// came from: the player, or the API it came through. Call after
+// GetMetadata.
+type SourceReporter interface {
+	Source() string
+}
+
+// Seeker is implemented by controllers that can jump within the track
+// (clicking the progress bar)
+type Seeker interface {
+	Seek(seconds float64) error
+}
+
+// VolumeController is implemented by controllers that can change the
+// player's volume (the scroll wheel, :volume). Volumes are a share of full
+// volume: delta e.g. 0.05 or -0.05, level 0 to 1.
+type VolumeController interface {
+	ChangeVolume(delta float64) error
+	SetVolume(level float64) error
+}
+
+// PlayerSelector is implemented by controllers that can follow one player
+// rather than whichever is active (:player). "auto" goes back to the
+// active one.
+type PlayerSelector interface {
+	Players() ([]string, error)
+	SetPlayer(name string) error
+}
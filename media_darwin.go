@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"os"
 	"os/exec"
@@ -35,6 +36,8 @@ type HybridController struct {
 	// goroutines, and a scheduled fetch can overlap a key-triggered fetch.
 	mu                   sync.Mutex
 	currentPlayer        string
+	currentSource        string    // Where metadata last came from: "MediaRemote" or the player
+	pinnedPlayer         string    // Only this player, over AppleScript (:player; "" for any)
 	mediaRemoteDownUntil time.Time // Skip MediaRemote until this time after a failure
 	cachedDuration       int64     // Cached duration from last metadata call
 	cachedPosition       float64   // Cached position from last metadata call
@@ -72,13 +75,15 @@ func NewMediaController() MediaController {
 }
 
 // useMediaRemote reports whether MediaRemote should be tried right now.
+// MediaRemote only sees the system's now playing app, so not while a
+// player is pinned.
 func (h *HybridController) useMediaRemote() bool {
 	if h.helperPath == "" {
 		return false
 	}
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	return time.Now().After(h.mediaRemoteDownUntil)
+	return h.pinnedPlayer == "" && time.Now().After(h.mediaRemoteDownUntil)
 }
 
 // markMediaRemoteFailed disables MediaRemote for a while so we fall back to
@@ -104,9 +109,18 @@ func (h *HybridController) runAppleScript(script string) (string, error) {
 	return strings.TrimSpace(out.String()), nil
 }
 
-// findActivePlayer checks if Music or Spotify are playing
+// scriptablePlayers are the apps AppleScript can drive
+var scriptablePlayers = []string{"Music", "Spotify"}
+
+// findActivePlayer checks if Music or Spotify (or just the pinned player)
+// are playing
 func (h *HybridController) findActivePlayer() (string, error) {
-	players := []string{"Music", "Spotify"}
+	players := scriptablePlayers
+	h.mu.Lock()
+	if h.pinnedPlayer != "" {
+		players = []string{h.pinnedPlayer}
+	}
+	h.mu.Unlock()
 
 	for _, player := range players {
 		checkScript := fmt.Sprintf(`
@@ -159,6 +173,9 @@ func (h *HybridController) GetMetadata() (title, artist, album, status string, e
 		if err == nil && output != "" {
 			parts := strings.Split(output, "|")
 			if len(parts) >= 4 {
+				h.mu.Lock()
+				h.currentSource = "MediaRemote"
+				h.mu.Unlock()
 				return strings.TrimSpace(parts[0]),
 					strings.TrimSpace(parts[1]),
 					strings.TrimSpace(parts[2]),
@@ -178,6 +195,7 @@ func (h *HybridController) GetMetadata() (title, artist, album, status string, e
 
 	h.mu.Lock()
 	h.currentPlayer = player
+	h.currentSource = player
 	h.mu.Unlock()
 
 	// Get all data in a single AppleScript call for performance
@@ -293,21 +311,17 @@ func (h *HybridController) Control(command string) error {
 	}
 
 	// Fallback to AppleScript
-	h.mu.Lock()
-	player := h.currentPlayer
-	h.mu.Unlock()
-	if player == "" {
-		var err error
-		player, err = h.findActivePlayer()
-		if err != nil {
-			return err
-		}
+	player, err := h.scriptPlayer()
+	if err != nil {
+		return err
 	}
 
 	var script string
 	switch command {
 	case "play-pause":
 		script = fmt.Sprintf(`tell application "%s" to playpause`, player)
+	case "pause":
+		script = fmt.Sprintf(`tell application "%s" to pause`, player)
 	case "next":
 		script = fmt.Sprintf(`tell application "%s" to next track`, player)
 	case "previous":
@@ -316,10 +330,98 @@ func (h *HybridController) Control(command string) error {
 		return fmt.Errorf("unknown command: %s", command)
 	}
 
-	_, err := h.runAppleScript(script)
+	_, err = h.runAppleScript(script)
 	return err
 }
 
+// scriptPlayer is the player AppleScript commands go to: the one metadata
+// last came from, or else whichever is playing
+func (h *HybridController) scriptPlayer() (string, error) {
+	h.mu.Lock()
+	player := h.currentPlayer
+	h.mu.Unlock()
+	if player == "" {
+		return h.findActivePlayer()
+	}
+	return player, nil
+}
+
+// adjustablePlayer is the player seeks and volume changes go to. Only
+// AppleScript can make them, so not while metadata comes from MediaRemote:
+// whatever app that is, a script would reach Music or Spotify instead.
+func (h *HybridController) adjustablePlayer() (string, error) {
+	if h.Source() == "MediaRemote" {
+		return "", ErrUnsupported
+	}
+	return h.scriptPlayer()
+}
+
+// Seek implements Seeker. Spotify and Music both take "player position"
+// in seconds; the MediaRemote helper can't seek.
+func (h *HybridController) Seek(seconds float64) error {
+	player, err := h.adjustablePlayer()
+	if err != nil {
+		return err
+	}
+	_, err = h.runAppleScript(fmt.Sprintf(`tell application "%s" to set player position to %.2f`, player, seconds))
+	return err
+}
+
+// ChangeVolume implements VolumeController on the player's own volume
+// (0-100 in both Spotify and Music)
+func (h *HybridController) ChangeVolume(delta float64) error {
+	player, err := h.adjustablePlayer()
+	if err != nil {
+		return err
+	}
+	_, err = h.runAppleScript(fmt.Sprintf(`tell application "%s" to set sound volume to (sound volume + %d)`, player, int(delta*100)))
+	return err
+}
+
+// SetVolume implements VolumeController
+func (h *HybridController) SetVolume(level float64) error {
+	player, err := h.adjustablePlayer()
+	if err != nil {
+		return err
+	}
+	_, err = h.runAppleScript(fmt.Sprintf(`tell application "%s" to set sound volume to %d`, player, int(math.Round(level*100))))
+	return err
+}
+
+// Source implements SourceReporter
+func (h *HybridController) Source() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.currentSource
+}
+
+// Players implements PlayerSelector
+func (h *HybridController) Players() ([]string, error) {
+	return scriptablePlayers, nil
+}
+
+// SetPlayer implements PlayerSelector: pins Music or Spotify (any case),
+// or "auto" for whichever is playing
+func (h *HybridController) SetPlayer(name string) error {
+	pinned := ""
+	if name != "auto" {
+		for _, player := range scriptablePlayers {
+			if strings.EqualFold(name, player) {
+				pinned = player
+			}
+		}
+		if pinned == "" {
+			return fmt.Errorf("unknown player '%s' (%s or auto)", name, strings.Join(scriptablePlayers, ", "))
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pinnedPlayer = pinned
+	h.currentPlayer = pinned
+	return nil
+}
+
 func (h *HybridController) GetArtwork() ([]byte, error) {
 	// Try MediaRemote first - helper returns base64, decode to raw bytes here
 	if h.useMediaRemote() {
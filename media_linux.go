@@ -5,8 +5,10 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/url"
 	"os"
@@ -29,6 +31,10 @@ type PlayerctlController struct {
 	cachedDuration int64
 	cachedPosition float64
 	cachedArtURL   string
+	cachedYear     string
+	cachedSource   string
+	cachedInstance string // MPRIS name of the player metadata came from, e.g. "spotify.instance1234"
+	player         string // --player for every call ("" follows the active one)
 }
 
 // NewMediaController creates a new media controller for the current platform
@@ -40,8 +46,8 @@ func (p *PlayerctlController) GetMetadata() (title, artist, album, status string
 	// Single invocation for all fields. Tab separator avoids conflicts with | in
 	// metadata (e.g. album names like "Artist | Sessions"). Missing fields
 	// (mpris:length on radio streams, etc.) render as empty strings.
-	cmd := exec.Command("playerctl", "metadata", "--format",
-		"{{title}}\t{{artist}}\t{{album}}\t{{status}}\t{{mpris:length}}\t{{position}}\t{{mpris:artUrl}}")
+	cmd := p.playerctl("metadata", "--format",
+		"{{title}}\t{{artist}}\t{{album}}\t{{status}}\t{{mpris:length}}\t{{position}}\t{{mpris:artUrl}}\t{{xesam:contentCreated}}\t{{playerName}}\t{{playerInstance}}")
 	var out bytes.Buffer
 	cmd.Stdout = &out
 	if err := cmd.Run(); err != nil {
@@ -55,8 +61,8 @@ func (p *PlayerctlController) GetMetadata() (title, artist, album, status string
 	}
 
 	parts := strings.Split(output, "\t")
-	if len(parts) != 7 {
-		return "", "", "", "", fmt.Errorf("unexpected metadata format: got %d parts, expected 7", len(parts))
+	if len(parts) != 10 {
+		return "", "", "", "", fmt.Errorf("unexpected metadata format: got %d parts, expected 10", len(parts))
 	}
 
 	// Duration and position are best-effort: some players/streams don't report
@@ -74,6 +80,9 @@ func (p *PlayerctlController) GetMetadata() (title, artist, album, status string
 	p.cachedDuration = duration
 	p.cachedPosition = position
 	p.cachedArtURL = strings.TrimSpace(parts[6])
+	p.cachedYear = releaseYear(strings.TrimSpace(parts[7]))
+	p.cachedSource = strings.TrimSpace(parts[8])
+	p.cachedInstance = strings.TrimSpace(parts[9])
 	p.mu.Unlock()
 
 	return strings.TrimSpace(parts[0]),
@@ -89,6 +98,32 @@ func (p *PlayerctlController) GetDuration() (int64, error) {
 	return p.cachedDuration, nil
 }
 
+// GetYear implements YearProvider from xesam:contentCreated
+func (p *PlayerctlController) GetYear() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.cachedYear
+}
+
+// Source implements SourceReporter: the player metadata came from
+func (p *PlayerctlController) Source() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.cachedSource
+}
+
+// releaseYear takes the year from an xesam:contentCreated date such as
+// "2019-05-17T00:00:00Z" ("" if it doesn't start with one)
+func releaseYear(created string) string {
+	if len(created) < 4 {
+		return ""
+	}
+	if _, err := strconv.Atoi(created[:4]); err != nil {
+		return ""
+	}
+	return created[:4]
+}
+
 func (p *PlayerctlController) GetPosition() (float64, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -96,12 +131,136 @@ func (p *PlayerctlController) GetPosition() (float64, error) {
 }
 
 func (p *PlayerctlController) Control(command string) error {
-	if err := exec.Command("playerctl", command).Run(); err != nil {
+	if err := p.playerctl(command).Run(); err != nil {
 		return fmt.Errorf("playerctl %s failed: %w", command, err)
 	}
 	return nil
 }
 
+// Seek implements Seeker
+func (p *PlayerctlController) Seek(seconds float64) error {
+	if p.playerLacks("CanSeek") {
+		return ErrUnsupported
+	}
+	position := strconv.FormatFloat(seconds, 'f', 2, 64)
+	if err := p.playerctl("position", position).Run(); err != nil {
+		return fmt.Errorf("playerctl position failed: %w", err)
+	}
+	return nil
+}
+
+// ChangeVolume implements VolumeController ("playerctl volume 0.05+")
+func (p *PlayerctlController) ChangeVolume(delta float64) error {
+	if p.playerLacks("Volume") {
+		return ErrUnsupported
+	}
+	sign := "+"
+	if delta < 0 {
+		sign = "-"
+	}
+	step := strconv.FormatFloat(math.Abs(delta), 'f', 2, 64) + sign
+	if err := p.playerctl("volume", step).Run(); err != nil {
+		return fmt.Errorf("playerctl volume failed: %w", err)
+	}
+	return nil
+}
+
+// SetVolume implements VolumeController ("playerctl volume 0.40")
+func (p *PlayerctlController) SetVolume(level float64) error {
+	if p.playerLacks("Volume") {
+		return ErrUnsupported
+	}
+	if err := p.playerctl("volume", strconv.FormatFloat(level, 'f', 2, 64)).Run(); err != nil {
+		return fmt.Errorf("playerctl volume failed: %w", err)
+	}
+	return nil
+}
+
+// playerLacks reports whether the player metadata came from says it can't
+// do something, reading its MPRIS property over D-Bus: a CanSeek that's
+// false, or a Volume it doesn't have. When there's no telling (no gdbus, the
+// player quit, D-Bus timed out) it's assumed it can, so playerctl runs and
+// reports any real failure.
+func (p *PlayerctlController) playerLacks(property string) bool {
+	p.mu.Lock()
+	instance := p.cachedInstance
+	p.mu.Unlock()
+	if instance == "" {
+		return false
+	}
+	out, err := exec.Command("gdbus", "call", "--session",
+		"--dest", "org.mpris.MediaPlayer2."+instance,
+		"--object-path", "/org/mpris/MediaPlayer2",
+		"--method", "org.freedesktop.DBus.Properties.Get",
+		"org.mpris.MediaPlayer2.Player", property).Output()
+	return mprisLacks(out, err)
+}
+
+// mprisLacks interprets a gdbus property read: true for a false reply, or
+// the player saying it has no such property
+func mprisLacks(out []byte, err error) bool {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		stderr := string(exitErr.Stderr)
+		return strings.Contains(stderr, "org.freedesktop.DBus.Error.UnknownProperty") ||
+			strings.Contains(stderr, "org.freedesktop.DBus.Error.InvalidArgs")
+	}
+	return err == nil && strings.Contains(string(out), "false")
+}
+
+// Players implements PlayerSelector: the running players, as playerctl
+// names them
+func (p *PlayerctlController) Players() ([]string, error) {
+	out, err := exec.Command("playerctl", "--list-all").Output()
+	if err != nil {
+		return nil, fmt.Errorf("playerctl --list-all failed: %w", err)
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// SetPlayer implements PlayerSelector. name is a playerctl player name
+// ("spotify" also matches instances like "spotify.instance1234"), and must
+// be running.
+func (p *PlayerctlController) SetPlayer(name string) error {
+	if name == "auto" {
+		name = ""
+	}
+	if name != "" {
+		running, err := p.Players()
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, player := range running {
+			if player == name || strings.HasPrefix(player, name+".") {
+				found = true
+			}
+		}
+		if !found {
+			if len(running) == 0 {
+				return fmt.Errorf("no player '%s' (none running)", name)
+			}
+			return fmt.Errorf("no player '%s' (running: %s)", name, strings.Join(running, ", "))
+		}
+	}
+
+	p.mu.Lock()
+	p.player = name
+	p.mu.Unlock()
+	return nil
+}
+
+// playerctl is a playerctl command for the selected player
+func (p *PlayerctlController) playerctl(args ...string) *exec.Cmd {
+	p.mu.Lock()
+	player := p.player
+	p.mu.Unlock()
+	if player != "" {
+		args = append([]string{"--player=" + player}, args...)
+	}
+	return exec.Command("playerctl", args...)
+}
+
 func (p *PlayerctlController) GetArtwork() ([]byte, error) {
 	p.mu.Lock()
 	artURL := p.cachedArtURL
@@ -0,0 +1,34 @@
+//go:build linux
+
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+)
+
+// TestReleaseYear verifies years are taken from xesam:contentCreated dates
+func TestReleaseYear(t *testing.T) {
+	assertEqual(t, releaseYear("2019-05-17T00:00:00Z"), "2019", "full date")
+	assertEqual(t, releaseYear("1969"), "1969", "year only")
+	assertEqual(t, releaseYear(""), "", "missing")
+	assertEqual(t, releaseYear("May 2019"), "", "not a date")
+}
+
+// TestMprisLacks verifies only the player's own answer counts as lacking a
+// property, not gdbus failing to ask
+func TestMprisLacks(t *testing.T) {
+	exitErr := func(stderr string) error {
+		return &exec.ExitError{Stderr: []byte(stderr)}
+	}
+	assertEqual(t, mprisLacks([]byte("(<false>,)\n"), nil), true, "false reply")
+	assertEqual(t, mprisLacks([]byte("(<true>,)\n"), nil), false, "true reply")
+	assertEqual(t, mprisLacks([]byte("(<0.5>,)\n"), nil), false, "volume reply")
+	assertEqual(t, mprisLacks(nil, exitErr("Error: GDBus.Error:org.freedesktop.DBus.Error.UnknownProperty: Unknown property Volume")), true, "unknown property")
+	assertEqual(t, mprisLacks(nil, exitErr("Error: GDBus.Error:org.freedesktop.DBus.Error.InvalidArgs: No such property")), true, "invalid args")
+	assertEqual(t, mprisLacks(nil, exitErr("Error: GDBus.Error:org.freedesktop.DBus.Error.ServiceUnknown: The name is not activatable")), false, "player quit")
+	assertEqual(t, mprisLacks(nil, exitErr("Error: GDBus.Error:org.freedesktop.DBus.Error.NoReply: Timeout was reached")), false, "timeout")
+	assertEqual(t, mprisLacks(nil, exitErr("Error connecting: Cannot autolaunch D-Bus without X11 $DISPLAY")), false, "no session bus")
+	assertEqual(t, mprisLacks(nil, errors.New("exec: \"gdbus\": executable file not found in $PATH")), false, "no gdbus")
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// miniText is what mini mode scrolls: "artist — title"
+func (m model) miniText() string {
+	if m.songData.Artist == "" {
+		return m.songData.Title
+	}
+	return m.songData.Artist + " — " + m.songData.Title
+}
+
+// renderMini renders the mini layout: one line with the status icon, the
+// scrolling "artist — title", a small progress bar and the time. No border,
+// no artwork.
+func (m model) renderMini(cfg Config, lay layout, styles themeStyles) (string, hitMap) {
+	var line strings.Builder
+	var bar region
+	icons := iconsFor(cfg)
+
+	// Artwork placed by another layout before a resize has to go
+	if m.artworkEncoded != "" && m.supportsKitty {
+		line.WriteString(kittyDeletePlacements)
+	}
+
+	switch {
+	case m.prompt.active:
+		// The command prompt takes the line while it's open
+		line.WriteString(m.promptLine(styles, lay.boxWidth))
+	case m.toast.text != "" && m.lastError != nil:
+		line.WriteString(m.toastLine(styles, lay.boxWidth))
+	case errors.Is(m.lastError, ErrNothingPlaying):
+		line.WriteString(styles.title.Render(withIcon(icons.nowPlaying, "")) + styles.muted.Render("Nothing playing"))
+	case m.lastError != nil:
+		line.WriteString(styles.error.Render(scrollText("Error: "+m.lastError.Error(), lay.boxWidth, 0)))
+	default:
+		text := fitText(m.miniText(), lay.textWidth, m.overflow(cfg, lay), m.scrollOffset(0))
+		if m.toast.text != "" {
+			// A toast stands in for the text; the bar and time stay
+			text = m.toastLine(styles, lay.textWidth)
+		}
+		line.WriteString(styles.label.Render(withIcon(icons.label(icons.status(m.songData.Status)), "")) + text)
+
+		if m.duration > 0 {
+			currentPos := m.getCurrentPosition()
+			progress := min(currentPos/float64(m.duration), 1)
+			// Pad the text so the bar and time stay put while it scrolls
+			line.WriteString(strings.Repeat(" ", max(lay.textWidth-lipgloss.Width(text), 0)+1))
+			timeText := m.timeText(cfg)
+			if barWidth := progressBarWidth(lay.barWidth, timeText); barWidth > 0 {
+				bar = region{lipgloss.Width(line.String()), 0, barWidth, 1}
+				line.WriteString(renderBar(cfg, styles, barWidth, progress) + " ")
+			}
+			if m.hoverBar {
+				// The time under the pointer, in the same room
+				timeText = lipgloss.NewStyle().Width(cellWidth(timeText)).Render(formatTime(int64(m.hoverPosition)))
+			}
+			line.WriteString(styles.title.Render(timeText))
+		}
+	}
+
+	x, y := m.placeOffset(cfg, line.String(), lipgloss.Left)
+	hits := hitMap{card: region{x, y, lipgloss.Width(line.String()), 1}}
+	if bar.w > 0 {
+		hits.bar = region{x + bar.x, y, bar.w, 1}
+	}
+	return m.place(cfg, line.String(), lipgloss.Left), hits
+}
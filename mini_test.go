@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMiniLayout verifies mini is picked by config or a short terminal, and
+// divides the line between text, bar and time
+func TestMiniLayout(t *testing.T) {
+	wide := computeLayout(layoutTestConfig(layoutMini), 80, 1, true)
+	assertEqual(t, wide.name, layoutMini, "mini layout")
+	assertEqual(t, wide.artColumns, 0, "mini artwork")
+	assertEqual(t, wide.barWidth, miniBarWidth, "mini bar")
+	assertEqual(t, 2+wide.textWidth+1+wide.barWidth+progressTimeWidth, 80, "mini line width")
+
+	narrow := computeLayout(layoutTestConfig(layoutMini), 40, 1, true)
+	assertEqual(t, narrow.barWidth, 0, "bar dropped when narrow")
+
+	for _, name := range []string{layoutCard, layoutVertical, layoutFullscreen, layoutAuto} {
+		if got := computeLayout(layoutTestConfig(name), 80, miniMaxHeight-1, true).name; got != layoutMini {
+			t.Errorf("%s at %d rows: got %s, want mini", name, miniMaxHeight-1, got)
+		}
+	}
+	if got := computeLayout(layoutTestConfig(layoutCard), 80, miniMaxHeight, true).name; got != layoutCard {
+		t.Errorf("card at %d rows: got %s", miniMaxHeight, got)
+	}
+}
+
+// TestViewMini verifies mini renders one line and scrolls "artist — title"
+func TestViewMini(t *testing.T) {
+	defer config.Set(config.Get())
+	cfg := layoutTestConfig(layoutMini)
+	cfg.Timing.UIRefreshMs = 100
+	config.Set(cfg)
+
+	m := model{width: 60, height: 1, duration: 200, lastPosition: 65}
+	m.songData = SongData{Title: "Title", Artist: "Artist", Status: "Paused", TotalTime: "03:20"}
+
+	view := m.View()
+	if lines := strings.Count(strings.TrimRight(view, "\n"), "\n") + 1; lines != 1 {
+		t.Errorf("mini rendered %d lines", lines)
+	}
+	for _, want := range []string{"Artist — Title", "01:05/03:20", iconSets["nerd"].pause} {
+		if !strings.Contains(view, want) {
+			t.Errorf("mini view missing %q: %q", want, view)
+		}
+	}
+
+	// Scrolling follows the combined line, one character per step once
+	// the pause is over
+	m.songData.Title = strings.Repeat("Long title ", 10)
+	start := time.Now()
+	updated, _ := m.Update(tickMsg(start))
+	updated, _ = updated.(model).Update(tickMsg(start.Add(3*time.Second + 400*time.Millisecond)))
+	if got := updated.(model).scrollOffset(0); got != 2 {
+		t.Errorf("scroll offset = %d, want 2", got)
+	}
+}
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"hash/fnv"
+	"math"
+	"slices"
 	"strings"
 	"time"
 
@@ -22,11 +26,14 @@ const (
 	tickRatePaused  = 500 * time.Millisecond  // Reduced frequency when paused
 	tickRateIdle    = 1000 * time.Millisecond // Minimal updates when idle
 
-	// Text scrolling constants
-	scrollInterval     = 3  // Scroll every 3rd tick
-	scrollPauseTicks   = 30 // Pause duration at start/end of scroll (in ticks)
+	// Text scrolling constants (speed and pauses are text.scroll_*)
 	scrollSeparator    = "  •  "
-	scrollSeparatorLen = 5 // Length of "  •  " in runes
+	scrollSeparatorLen = 5 // Length of "  •  " in graphemes
+
+	// kittyUploadFlushDelay is how long a pending artwork upload keeps being
+	// emitted by View. Comfortably longer than a renderer frame (~16ms), so at
+	// least one render carrying the data reaches the terminal.
+	kittyUploadFlushDelay = 100 * time.Millisecond
 )
 
 // SongData holds the current track metadata
@@ -35,6 +42,7 @@ type SongData struct {
 	Title       string
 	Artist      string
 	Album       string
+	Year        string
 	CurrentTime string
 	TotalTime   string
 	Progress    float64
@@ -42,12 +50,16 @@ type SongData struct {
 
 // model is the Bubble Tea model for the TUI application
 type model struct {
-	songData        SongData
-	color           string
-	width           int
-	height          int
-	lastError       error
-	mediaController MediaController
+	songData          SongData
+	color             string
+	palette           palette  // Colors extracted from the artwork in auto color mode (empty = defaults)
+	paletteStrategy   string   // ui.palette the palette was extracted with
+	paletteBackground [3]uint8 // Background the palette was made readable on
+	backgroundSetting string   // ui.background as last seen, to ask the terminal again on a switch to auto
+	width             int
+	height            int
+	lastError         error
+	mediaController   MediaController
 
 	// For smooth position interpolation
 	lastPosition     float64   // Last known position in seconds
@@ -56,27 +68,83 @@ type model struct {
 	isPlaying        bool      // Whether song is currently playing
 
 	// Album artwork support
-	artworkEncoded  string // Kitty protocol-encoded artwork for display
-	supportsKitty   bool   // Whether terminal supports Kitty graphics
-	lastTrackID     string // Track ID for caching (title+artist) — controls scroll reset and vinyl cache
-	lastArtworkHash uint64 // Hash of last displayed artwork — triggers re-encode only when artwork actually changes
-	rawArtworkData  []byte // Raw artwork data for vinyl rotation re-encoding
-	forceDeleteImg  bool   // Force delete image on next render (for resize cleanup)
-
-	// Vinyl record animation (easter egg)
-	vinylRotation     int      // Current rotation angle (0-89 or 0-44) for spinning record effect
-	vinylFrameCache   []string // Pre-rendered vinyl frames for smooth playback (45 or 90 frames)
-	vinylCacheTrackID string   // Track ID for which frames are cached
-	vinylAccumulator  float64  // Fractional frame accumulator for smooth rotation at any RPM
-	vinylCachedFrames int      // Number of frames in cache (for detecting config changes)
-
-	// Text scrolling state
-	scrollOffset int // Current scroll position for text animation
-	scrollPause  int // Pause counter at start/end of scroll
-	scrollTick   int // Tick counter for slowing scroll speed
+	artworkEncoded    string             // Kitty transmit command for the current artwork (uploaded once, then placed)
+	supportsKitty     bool               // Whether terminal supports Kitty graphics
+	supportsKittyAnim bool               // Whether terminal can play Kitty animations by itself
+	lastTrackID       string             // Track ID for caching (title+artist) — controls scroll reset and animation cache
+	lastArtworkHash   uint64             // Hash of last displayed artwork — triggers re-encode only when artwork actually changes
+	rawArtworkData    []byte             // Raw artwork data for rendering animation frames
+	forceDeleteImg    bool               // Force delete image on next render (for resize cleanup)
+	artworkFilters    string             // artwork.filters the current artwork was processed with (joined)
+	artworkCancel     context.CancelFunc // Cancels in-flight artwork processing (new track)
+
+	// Kitty upload tracking — artworkEncoded is only sent while an upload is
+	// pending; every other render just emits a small placement command
+	artworkUploadPending bool // Whether View should (re-)send artworkEncoded
+	artworkUploadSeq     int  // Bumped on each new upload so stale acks are ignored
+
+	// Artwork animation (artwork.animation, e.g. the vinyl record easter egg)
+	animFrame        int                // Current frame index (0 to len(animFrameCache)-1)
+	animFrameCache   []string           // Pre-rendered frames, filled progressively ("" = not rendered yet)
+	animStep         int                // Frames advanced per step: coarse while rendering, 1 when complete, 0 = not ready
+	animCacheTrackID string             // Track ID for which frames are cached or being generated
+	animAccumulator  float64            // Fractional frame accumulator for smooth playback at any speed
+	animCachedName   string             // Animation the cache was generated for (for detecting config changes)
+	animCachedFrames int                // Frame limit the cache was generated with (for detecting config changes)
+	animCachedBudget int                // vinyl_memory_mb the cache was generated with (for detecting config changes)
+	animJob          int                // Current frame generation job; results from older jobs are dropped
+	animCancel       context.CancelFunc // Cancels the in-flight frame generation job
+	animNative       bool               // Frames live in the terminal as a Kitty animation (no per-tick swapping)
+	animCachedCycle  time.Duration      // Loop duration baked into the terminal animation's frame gaps
+	animCycle        time.Duration      // Loop duration reported by the current job
+	animatedCover    bool               // Current artwork is an animated GIF/WebP (artwork.animated_covers)
+
+	// Cover transition (artwork.transition) - the old cover blends into the new
+	// one; artwork arriving meanwhile is held in transitionFinal
+	transitionJob    int      // Current transition job; results from older jobs are dropped
+	transitioning    bool     // A transition is rendering or playing
+	transitionFrames []string // In-between frames (Kitty transmit commands)
+	transitionIndex  int      // Next frame to show
+	transitionFinal  string   // Artwork to show when the transition ends
+
+	// Accent color fade toward the new cover's color (over transition_ms)
+	colorFadeFrom  [3]uint8  // Color the fade started from
+	colorFadeTo    string    // Target color ("" = no fade running)
+	colorFadeStart time.Time // When the fade started
+
+	// Vinyl tonearm overlay - a separate Kitty image layered over the record,
+	// re-rendered whenever progress moves it by a whole degree
+	tonearmEncoded       string // Kitty transmit command for the current overlay
+	tonearmAngle         int    // Angle the current overlay was rendered at
+	tonearmRequested     int    // Angle of the overlay being rendered (avoids duplicate work)
+	tonearmUploadPending bool   // Whether View should (re-)send tonearmEncoded
+	tonearmUploadSeq     int    // Bumped on each new overlay so stale acks are ignored
+
+	// Text scrolling state, one per line (mini has the one)
+	scrolls []scrollState
+
+	// keys: as a keymap, built when the config changes rather than on
+	// every key press and render
+	keys keymap
+
+	// Mouse pointer over the progress bar, and the time under it
+	hoverBar      bool
+	hoverPosition float64
+	hits          *hitMap // Where the last View put the clickable parts (shared by copies, as View can't change the model)
+
+	// Notice where the help line is (toast.go), and where metadata comes
+	// from (for telling when that switches)
+	toast  toast
+	source string
+
+	// Command prompt (":"), and the :sleep timer
+	prompt     commandPrompt
+	sleepUntil time.Time // When playback pauses (zero = no timer)
+	sleepSeq   int       // Bumped by each :sleep so older timers are ignored
 
 	// UI state
 	showHelp bool // Whether to show help text
+	quitting bool // Quit requested: the last render clears the UI
 }
 
 // UI refresh tick - fires every 100ms for smooth rendering
@@ -91,6 +159,8 @@ type songDataMsg struct {
 	artist      string
 	album       string
 	status      string
+	year        string // Empty unless the controller is a YearProvider
+	source      string // Empty unless the controller is a SourceReporter
 	duration    int64
 	position    float64
 	rawArtwork  []byte // Raw artwork data
@@ -98,10 +168,46 @@ type songDataMsg struct {
 	err         error
 }
 
-// Result of generating vinyl frames in background
-type vinylFramesMsg struct {
-	frames  []string // Pre-rendered vinyl frames (45 frames)
-	trackID string   // Track ID these frames belong to
+// Result of processing artwork in the background (processArtworkCmd)
+type artworkReadyMsg struct {
+	trackID     string // Track the artwork was fetched for
+	artworkHash uint64 // Hash of rawArtwork (stale results are dropped)
+	rawArtwork  []byte
+	filters     string   // artwork.filters it was processed with (joined)
+	colors      palette  // Extracted palette (empty unless auto color mode)
+	strategy    string   // ui.palette it was extracted with
+	background  [3]uint8 // Background it was made readable on
+	encoded     string   // Kitty transmit command
+	err         error
+}
+
+// Result of extracting the palette again for a new ui.palette or
+// background (paletteCmd)
+type paletteMsg struct {
+	trackID     string   // Track the artwork belongs to
+	artworkHash uint64   // Hash of the artwork it was extracted from
+	colors      palette  // Extracted palette
+	strategy    string   // ui.palette it was extracted with
+	background  [3]uint8 // Background it was made readable on
+}
+
+// Result of uploading animation frames as a terminal-side Kitty animation
+type animationUploadMsg struct {
+	job     int           // Generation job these frames belong to
+	upload  string        // Kitty commands uploading every frame under kittyImageID
+	trackID string        // Track ID these frames belong to
+	cycle   time.Duration // Loop duration the frame gaps were computed for
+}
+
+// Result of a command run in the background from the prompt
+type commandResultMsg struct {
+	text string // Shown where the help line is
+	err  error
+}
+
+// The :sleep timer with the given sequence number ran out
+type sleepMsg struct {
+	seq int
 }
 
 // Result of a playback control action (play-pause/next/previous)
@@ -112,14 +218,26 @@ type controlMsg struct {
 // Clear the forceDeleteImg flag after one render cycle
 type clearDeleteFlagMsg struct{}
 
-// vinylTickRate returns the UI tick rate synced to the vinyl frame rate,
-// clamped to [50ms, 300ms]. Shared by tickCmd and updateVinylRotation so the
-// rotation math always matches the actual tick interval.
-func vinylTickRate(cfg Config) time.Duration {
-	framesPerSecond := (cfg.Artwork.VinylRPM / 60.0) * float64(cfg.Artwork.VinylFrames)
-	if framesPerSecond <= 0 {
+// The renderer has flushed the artwork upload with the given sequence number
+type artworkUploadedMsg struct {
+	seq int
+}
+
+// The renderer has flushed the tonearm upload with the given sequence number
+type tonearmUploadedMsg struct {
+	seq int
+}
+
+// frameTickRate returns the UI tick rate synced to an animation playing
+// frames per loop of the given duration (fewer than the maximum under a
+// memory budget or at a coarse step), clamped to [50ms, 300ms]. Shared by
+// tickCmd and updateAnimationFrame so the playback math always matches the
+// actual tick interval.
+func frameTickRate(cycle time.Duration, frames int) time.Duration {
+	if cycle <= 0 || frames <= 0 {
 		return 0
 	}
+	framesPerSecond := float64(frames) / cycle.Seconds()
 	tickRate := time.Duration(1000.0/framesPerSecond) * time.Millisecond
 	if tickRate < 50*time.Millisecond {
 		tickRate = 50 * time.Millisecond
@@ -129,9 +247,58 @@ func vinylTickRate(cfg Config) time.Duration {
 	return tickRate
 }
 
+// frameGapMs returns how long each of frames is shown for when the terminal
+// plays a cycle-long animation itself. No clamping needed: ticks aren't
+// involved.
+func frameGapMs(cycle time.Duration, frames int) int {
+	if cycle <= 0 || frames <= 0 {
+		return 0
+	}
+	return int(math.Round(cycle.Seconds() * 1000 / float64(frames)))
+}
+
+// coverActive reports whether the current artwork is an animated cover that
+// should play its own frames (artwork.animated_covers, no animation chosen)
+func (m model) coverActive(cfg Config) bool {
+	return cfg.Artwork.AnimatedCovers && m.animatedCover && activeAnimation(cfg) == nil
+}
+
+// animationKey names what the frame cache holds: the animation mode, "cover"
+// for an animated cover, or none
+func (m model) animationKey(cfg Config) string {
+	if m.coverActive(cfg) {
+		return "cover"
+	}
+	return animationName(cfg)
+}
+
+// playbackCycle returns the loop duration of whatever is animating. Animation
+// modes follow the live config (e.g. vinyl_rpm); a cover's comes from its
+// frame delays, reported by the job rendering it.
+func (m model) playbackCycle(cfg Config) time.Duration {
+	if anim := activeAnimation(cfg); anim != nil {
+		return anim.cycle(cfg)
+	}
+	return m.animCycle
+}
+
+// useNativeAnimation reports whether the terminal should play the animation
+// (Kitty animation) instead of goplaying swapping pre-encoded frames on each
+// tick
+func (m model) useNativeAnimation(cfg Config) bool {
+	switch cfg.Artwork.VinylPlayback {
+	case "terminal":
+		return true
+	case "frames":
+		return false
+	default:
+		return m.supportsKittyAnim
+	}
+}
+
 // Schedule next UI refresh tick with adaptive rate
 // Playing: 100ms (smooth progress)
-// Playing (vinyl mode): 250ms (slower tick since we only update on frame changes)
+// Playing (animated artwork): synced to the animation's frame rate
 // Paused: 500ms (just scrolling, save CPU)
 // Idle/Error: 1000ms (minimal updates)
 func (m model) tickCmd() tea.Cmd {
@@ -145,14 +312,14 @@ func (m model) tickCmd() tea.Cmd {
 	} else if !m.isPlaying {
 		// Paused: medium rate (still need scrolling)
 		tickRate = tickRatePaused
-	} else if cfg.Artwork.VinylMode && len(m.vinylFrameCache) > 0 {
-		// Vinyl mode optimization: sync tick rate with vinyl frame rate
-		// to catch every frame change efficiently
-		if vt := vinylTickRate(cfg); vt > 0 {
+	} else if m.animStep > 0 {
+		// Animation optimization: sync tick rate with the animation's frame
+		// rate to catch every frame change efficiently
+		if vt := frameTickRate(m.playbackCycle(cfg), m.animEffectiveFrames()); vt > 0 {
 			tickRate = vt
 		}
 	}
-	// Playing (non-vinyl): use configured rate (default 100ms)
+	// Playing (static artwork): use configured rate (default 100ms)
 
 	return tea.Tick(tickRate, func(t time.Time) tea.Msg {
 		return tickMsg(t)
@@ -167,24 +334,6 @@ func fetchCmd() tea.Cmd {
 	})
 }
 
-// Generate vinyl frames in background (doesn't block UI)
-func generateVinylFramesCmd(rawArtwork []byte, trackID string, frameCount int) tea.Cmd {
-	return func() tea.Msg {
-		frames := make([]string, frameCount)
-
-		for i := 0; i < frameCount; i++ {
-			// Pass frame index and total frame count
-			if _, encoded, err := processArtwork(rawArtwork, false, i, frameCount); err == nil {
-				frames[i] = encoded
-			}
-		}
-		return vinylFramesMsg{
-			frames:  frames,
-			trackID: trackID,
-		}
-	}
-}
-
 // controlCmd runs a playback control action in the background, then fetches
 // fresh state. Running Control() outside Update keeps the UI responsive
 // (AppleScript on macOS can take 100ms+ per call).
@@ -198,13 +347,108 @@ func (m model) controlCmd(action string) tea.Cmd {
 	)
 }
 
+// showArtwork swaps in new Kitty transmit data and marks it for upload on the
+// next renders (see artworkUploadedCmd)
+func (m *model) showArtwork(encoded string) {
+	m.artworkEncoded = encoded
+	m.artworkUploadPending = true
+	m.artworkUploadSeq++
+}
+
+// artworkUploadedCmd stops re-sending a pending upload once the renderer has
+// had time to flush it. Returns nil when nothing is pending.
+func (m model) artworkUploadedCmd() tea.Cmd {
+	if !m.artworkUploadPending {
+		return nil
+	}
+	seq := m.artworkUploadSeq
+	return tea.Tick(kittyUploadFlushDelay, func(time.Time) tea.Msg {
+		return artworkUploadedMsg{seq: seq}
+	})
+}
+
+// clearAnimationCache drops cached animation frames (or the terminal
+// animation) so the next artwork is shown still or regenerated
+func (m *model) clearAnimationCache() {
+	if m.animCancel != nil {
+		// Stop rendering frames nobody will look at
+		m.animCancel()
+		m.animCancel = nil
+	}
+	kittyFiles.release(kittyOwnerAnimation(m.animJob))
+	m.animFrameCache = nil
+	m.animStep = 0
+	m.animCachedBudget = 0
+	m.animCacheTrackID = ""
+	m.animCachedName = ""
+	m.animCachedFrames = 0
+	m.animNative = false
+	m.animFrame = 0
+	m.animAccumulator = 0
+}
+
+// animationReady reports whether animation frames exist for the current
+// artwork, either cached locally or uploaded as a terminal animation
+func (m model) animationReady() bool {
+	return m.animStep > 0 || m.animNative
+}
+
+// animEffectiveFrames returns how many frames one loop currently
+// steps through (fewer while only coarse frames have been rendered)
+func (m model) animEffectiveFrames() int {
+	if m.animStep <= 0 {
+		return 0
+	}
+	return len(m.animFrameCache) / m.animStep
+}
+
+// showTonearm reports whether the vinyl tonearm overlay should be displayed
+func (m model) showTonearm(cfg Config) bool {
+	return animationName(cfg) == "vinyl" && cfg.Artwork.VinylTonearm &&
+		m.supportsKitty && cfg.Artwork.Enabled && m.artworkEncoded != ""
+}
+
+// updateTonearm requests a new tonearm overlay when playback progress has
+// moved it by a whole degree, and forgets it once it's no longer shown
+func (m *model) updateTonearm(cfg Config) tea.Cmd {
+	if !m.showTonearm(cfg) {
+		if m.tonearmEncoded != "" || m.tonearmRequested != 0 {
+			// View has been deleting it from the terminal since it was hidden
+			m.tonearmEncoded = ""
+			m.tonearmAngle = 0
+			m.tonearmRequested = 0
+			m.tonearmUploadPending = false
+			kittyFiles.release(kittyOwnerTonearm)
+		}
+		return nil
+	}
+
+	var progress float64
+	if m.duration > 0 {
+		progress = m.getCurrentPosition() / float64(m.duration)
+	}
+	angle := tonearmAngle(progress)
+	if angle == m.tonearmAngle || angle == m.tonearmRequested {
+		return nil
+	}
+	m.tonearmRequested = angle
+	return renderTonearmCmd(angle)
+}
+
 // resetArtworkState clears all cached artwork state so the next fetch
 // re-fetches and re-encodes. Must be called whenever artworkEncoded is
 // cleared, otherwise the unchanged hash suppresses re-encoding forever.
 func (m *model) resetArtworkState() {
 	m.artworkEncoded = ""
+	m.artworkUploadPending = false
 	m.lastTrackID = ""
 	m.lastArtworkHash = 0
+	m.cancelArtworkProcessing()
+	// Drop any transition without showing what it held back
+	m.transitioning = false
+	m.transitionFrames = nil
+	m.transitionFinal = ""
+	kittyFiles.release(kittyOwnerTransition)
 }
 
 // Fetch song data in background (doesn't block UI)
@@ -230,6 +474,15 @@ func (m model) fetchSongData() tea.Cmd {
 			position = 0
 		}
 
+		var year string
+		if yp, ok := m.mediaController.(YearProvider); ok {
+			year = yp.GetYear()
+		}
+		var source string
+		if sr, ok := m.mediaController.(SourceReporter); ok {
+			source = sr.Source()
+		}
+
 		// Fetch artwork if Kitty protocol is supported
 		var rawArtwork []byte
 		var artHash uint64
@@ -255,6 +508,8 @@ func (m model) fetchSongData() tea.Cmd {
 			artist:      artist,
 			album:       album,
 			status:      status,
+			year:        year,
+			source:      source,
 			duration:    duration,
 			position:    position,
 			rawArtwork:  rawArtwork,
@@ -263,6 +518,81 @@ func (m model) fetchSongData() tea.Cmd {
 	}
 }
 
+// processArtworkCmd decodes, encodes and extracts the color of new artwork
+// off the Update goroutine (big covers take 100+ ms), replacing any artwork
+// still being processed. The result arrives as an artworkReadyMsg tagged with
+// the track and hash it belongs to.
+func (m *model) processArtworkCmd(cfg Config, rawArtwork []byte, artworkHash uint64) tea.Cmd {
+	m.cancelArtworkProcessing()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.artworkCancel = cancel
+
+	trackID := m.lastTrackID
+	frameIndex, frameCount := m.animFrame, max(len(m.animFrameCache), 1)
+	extractColor := cfg.UI.ColorMode == "auto"
+	return func() (msg tea.Msg) {
+		ready := artworkReadyMsg{
+			trackID:     trackID,
+			artworkHash: artworkHash,
+			rawArtwork:  rawArtwork,
+			filters:     strings.Join(cfg.Artwork.Filters, ","),
+			strategy:    cfg.UI.Palette,
+			background:  backgroundFor(cfg),
+		}
+		// Malformed images can panic inside image decoders; artwork must
+		// never crash the UI, so report an error and keep the old art
+		defer func() {
+			if r := recover(); r != nil {
+				ready.err = fmt.Errorf("artwork processing panicked: %v", r)
+				msg = ready
+			}
+		}()
+
+		if ctx.Err() != nil {
+			return nil
+		}
+		ready.colors, ready.encoded, ready.err = processArtwork(ctx, cfg, rawArtwork, extractColor, frameIndex, frameCount)
+		if ctx.Err() != nil {
+			// A new track started meanwhile
+			return nil
+		}
+		return ready
+	}
+}
+
+// paletteCmd extracts the palette again from the artwork on screen, off the
+// Update goroutine. Only the colors change: the encoded artwork and any
+// animation frames stay as they are.
+func (m model) paletteCmd(cfg Config) tea.Cmd {
+	raw, trackID := m.rawArtworkData, m.lastTrackID
+	strategy, background := cfg.UI.Palette, backgroundFor(cfg)
+	return func() (msg tea.Msg) {
+		// As in processArtworkCmd, a bad image must never crash the UI
+		defer func() {
+			if recover() != nil {
+				msg = nil
+			}
+		}()
+		img, err := decodeArtworkData(raw)
+		if err != nil {
+			return nil
+		}
+		colors, err := extractPalette(img, strategy, background)
+		if err != nil {
+			return nil
+		}
+		return paletteMsg{trackID, hashBytes(raw), colors, strategy, background}
+	}
+}
+
+// cancelArtworkProcessing abandons artwork still being processed
+func (m *model) cancelArtworkProcessing() {
+	if m.artworkCancel != nil {
+		m.artworkCancel()
+		m.artworkCancel = nil
+	}
+}
+
 // Calculate current position with smooth interpolation
 func (m model) getCurrentPosition() float64 {
 	// If paused, return last known position
@@ -282,39 +612,48 @@ func (m model) getCurrentPosition() float64 {
 	return currentPos
 }
 
-// updateVinylRotation handles vinyl record rotation animation
+// updateAnimationFrame advances the artwork animation (vinyl rotation etc.)
 // Isolated function to minimize performance impact on normal mode
-func (m *model) updateVinylRotation(cfg Config) {
-	frameCount := cfg.Artwork.VinylFrames
-
-	// Early return if vinyl mode is disabled or not ready
-	if !cfg.Artwork.VinylMode || !m.isPlaying || len(m.vinylFrameCache) != frameCount {
+func (m *model) updateAnimationFrame(cfg Config) {
+	// Early return if no animation is active or it's not ready (or a cover
+	// transition is on screen)
+	if !m.isPlaying || m.animStep <= 0 || m.transitioning {
+		return
+	}
+	frameCount := len(m.animFrameCache)
+	cycle := m.playbackCycle(cfg)
+	if cycle <= 0 {
 		return
 	}
 
-	// Calculate how many frames to advance per tick based on RPM
-	// Formula: frames_per_second = RPM / 60 * frame_count
+	// Calculate how many frames to advance per tick based on the loop duration
+	// Formula: frames_per_second = frame_count / cycle_seconds
 	//          frames_per_tick = frames_per_second * tick_duration_seconds
-	framesPerSecond := cfg.Artwork.VinylRPM / 60.0 * float64(frameCount)
+	// While frames are still rendering, only every animStep-th frame exists,
+	// so a loop has fewer (bigger) steps
+	effectiveFrames := m.animEffectiveFrames()
+	framesPerSecond := float64(effectiveFrames) / cycle.Seconds()
 
 	// Use the actual tick interval (tickCmd syncs ticks to the frame rate when
-	// the vinyl cache is ready, which is the only state where we get here)
-	tickDuration := vinylTickRate(cfg).Seconds()
+	// the frame cache is ready, which is the only state where we get here)
+	tickDuration := frameTickRate(cycle, effectiveFrames).Seconds()
 	if tickDuration <= 0 {
 		return
 	}
 	framesPerTick := framesPerSecond * tickDuration
 
 	// Accumulate fractional frames
-	m.vinylAccumulator += framesPerTick
+	m.animAccumulator += framesPerTick
 
 	// Advance whole frames when accumulator >= 1
-	for m.vinylAccumulator >= 1.0 {
-		m.vinylRotation = (m.vinylRotation + 1) % frameCount
-		m.vinylAccumulator -= 1.0
+	for m.animAccumulator >= 1.0 {
+		// Snap to the current step (the step shrinks as more frames arrive,
+		// and every multiple of a coarser step is a multiple of a finer one)
+		m.animFrame = (m.animFrame/m.animStep*m.animStep + m.animStep) % frameCount
+		m.animAccumulator -= 1.0
 
 		// Use pre-cached frame - no expensive re-encoding!
-		m.artworkEncoded = m.vinylFrameCache[m.vinylRotation]
+		m.showArtwork(m.animFrameCache[m.animFrame])
 	}
 
 }
@@ -328,61 +667,106 @@ func (m model) Init() tea.Cmd {
 	)
 }
 
+// runAction does what a key (or the prompt) asks for: one of keyActions
+func (m model) runAction(action string) (model, tea.Cmd) {
+	switch action {
+	case actionQuit:
+		// The last render clears the UI, for inline mode's sake
+		m.quitting = true
+		return m, tea.Quit
+	case actionPlayPause:
+		// Run control in background, then fetch fresh state
+		return m, m.controlCmd("play-pause")
+	case actionNext:
+		return m, m.controlCmd("next")
+	case actionPrevious:
+		return m, m.controlCmd("previous")
+	case actionArtwork:
+		// Toggle artwork on/off
+		cfg := config.Get()
+		cfg.Artwork.Enabled = !cfg.Artwork.Enabled
+		config.Set(cfg)
+		m.notify(onOff("Artwork", cfg.Artwork.Enabled))
+		if !cfg.Artwork.Enabled {
+			// Clear artwork when disabling (including the hash, so
+			// re-enabling actually re-encodes)
+			m.resetArtworkState()
+		} else if m.supportsKitty {
+			// Re-fetch artwork when enabling
+			m.resetArtworkState()
+			return m, m.fetchSongData()
+		}
+		return m, nil
+	case actionAnimation:
+		// Cycle artwork animations (none → vinyl → cassette → ...)
+		cfg := config.Get()
+		cfg.Artwork.Animation = nextAnimationName(animationName(cfg))
+		cfg.Artwork.VinylMode = false // Superseded by the explicit choice
+		config.Set(cfg)
+		m.notify(animationToast(cfg.Artwork.Animation))
+
+		// Drop the old animation's frames and reload the artwork: the
+		// still becomes the new animation's first frame, and songDataMsg
+		// starts rendering the rest
+		m.clearAnimationCache()
+		if m.supportsKitty && cfg.Artwork.Enabled {
+			m.resetArtworkState() // Force re-fetch and re-encode (hash unchanged otherwise)
+			return m, m.fetchSongData()
+		}
+		return m, nil
+	case actionTime:
+		// Cycle the time beside the progress bar (total → elapsed → remaining)
+		cfg := config.Get()
+		cfg.UI.TimeDisplay = nextTimeDisplay(cfg.UI.TimeDisplay)
+		config.Set(cfg)
+		m.notify("Time: " + cfg.UI.TimeDisplay)
+		return m, nil
+	case actionCommand:
+		// Open the command prompt
+		m.prompt.active = true
+		m.prompt.input = ""
+		m.prompt.index = len(m.prompt.history)
+		return m, nil
+	case actionHelp:
+		// Toggle help text
+		m.showHelp = !m.showHelp
+		return m, nil
+	}
+	return m, nil
+}
+
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q":
-			return m, tea.Quit
-		case "p":
-			// Run control in background, then fetch fresh state
-			return m, m.controlCmd("play-pause")
-		case "n":
-			return m, m.controlCmd("next")
-		case "b":
-			return m, m.controlCmd("previous")
-		case "a":
-			// Toggle artwork on/off
-			cfg := config.Get()
-			cfg.Artwork.Enabled = !cfg.Artwork.Enabled
-			config.Set(cfg)
-			if !cfg.Artwork.Enabled {
-				// Clear artwork when disabling (including the hash, so
-				// re-enabling actually re-encodes)
-				m.resetArtworkState()
-			} else if m.supportsKitty {
-				// Re-fetch artwork when enabling
-				m.resetArtworkState()
-				return m, m.fetchSongData()
-			}
-			return m, nil
-		case "v":
-			// Toggle vinyl mode on/off
-			cfg := config.Get()
-			cfg.Artwork.VinylMode = !cfg.Artwork.VinylMode
-			config.Set(cfg)
-
-			if !cfg.Artwork.VinylMode {
-				// Switching from vinyl to normal: clear vinyl cache and reload normal artwork
-				m.vinylFrameCache = nil
-				m.vinylCacheTrackID = ""
-				m.vinylCachedFrames = 0
-				m.vinylRotation = 0
-				m.vinylAccumulator = 0
-				m.resetArtworkState() // Force re-fetch and re-encode (hash unchanged otherwise)
-				return m, m.fetchSongData()
-			} else {
-				// Switching from normal to vinyl: regenerate vinyl frames if we have artwork
-				if len(m.rawArtworkData) > 0 && m.lastTrackID != "" && m.supportsKitty {
-					return m, generateVinylFramesCmd(m.rawArtworkData, m.lastTrackID, cfg.Artwork.VinylFrames)
-				}
-			}
-			return m, nil
-		case "?":
-			// Toggle help text
-			m.showHelp = !m.showHelp
+		// The command prompt takes every key while it's open
+		if m.prompt.active {
+			return m.promptKey(msg)
+		}
+
+		// Keys come from the keymap (keys: in the config)
+		return m.runAction(m.keyBindings(config.Get()).action(msg.String()))
+
+	case commandResultMsg:
+		if msg.err != nil {
+			m.notifyError(msg.err)
+		} else {
+			m.notify(msg.text)
+		}
+		return m, nil
+
+	case sleepMsg:
+		// The :sleep timer ran out (unless a newer one replaced it)
+		if msg.seq != m.sleepSeq || m.sleepUntil.IsZero() {
 			return m, nil
 		}
+		m.sleepUntil = time.Time{}
+		m.notify("Sleep timer: paused")
+		// Pause rather than toggle: isPlaying is only as fresh as the last
+		// fetch, and pausing what's already paused does nothing
+		return m, m.controlCmd("pause")
+
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -400,37 +784,84 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case configReloadMsg:
+		// A config file that was rejected: say which settings (or why it
+		// didn't parse), and carry on with the old config
+		if len(msg.rejected) > 0 {
+			reason := strings.Join(configErrorFields(msg.rejected), ", ")
+			if reason == "" {
+				// Not a validation error: the file didn't parse
+				reason = msg.rejected[0].Error()
+			}
+			m.warn("Config not reloaded: " + reason)
+			return m, watchConfigCmd()
+		}
+
 		// Config file changed, update color and artwork setting
 		cfg := config.Get()
+
+		// Switching ui.background to auto asks the terminal again, as it may
+		// have changed since startup (a different answer is another reload)
+		watch := watchConfigCmd()
+		if cfg.UI.Background == backgroundAuto && m.backgroundSetting != "" && m.backgroundSetting != backgroundAuto {
+			watch = tea.Batch(watch, tea.Exec(backgroundQuery{}, nil))
+		}
+		m.backgroundSetting = cfg.UI.Background
+		m.keys = keymapFor(cfg)
+		applyColorProfile(cfg)
 		if cfg.UI.ColorMode == "manual" {
 			m.color = cfg.UI.Color
+			m.colorFadeTo = ""
+			m.palette = palette{}
 		}
 
-		// If vinyl mode was disabled, clear cache and reload normal artwork
-		if !cfg.Artwork.VinylMode && len(m.vinylFrameCache) > 0 {
-			m.vinylFrameCache = nil
-			m.vinylCacheTrackID = ""
-			m.vinylCachedFrames = 0
-			m.resetArtworkState() // Force re-fetch and re-encode of normal artwork
-			return m, tea.Batch(watchConfigCmd(), m.fetchSongData())
+		// If the palette strategy or background changed, extract the palette
+		// again. The artwork stays, unless it's a duotone tinted for the old
+		// background.
+		if cfg.UI.ColorMode == "auto" && m.artworkEncoded != "" && m.palette != (palette{}) &&
+			(cfg.UI.Palette != m.paletteStrategy || backgroundFor(cfg) != m.paletteBackground) {
+			if backgroundFor(cfg) != m.paletteBackground && slices.Contains(cfg.Artwork.Filters, "duotone") {
+				m.clearAnimationCache()
+				m.resetArtworkState()
+				return m, tea.Batch(watch, m.fetchSongData())
+			}
+			// Marked done now, so another reload meanwhile doesn't ask again
+			m.paletteStrategy, m.paletteBackground = cfg.UI.Palette, backgroundFor(cfg)
+			watch = tea.Batch(watch, m.paletteCmd(cfg))
+		}
+
+		// If the filters changed, re-process the artwork (and any animation
+		// frames made from it)
+		if m.artworkEncoded != "" && strings.Join(cfg.Artwork.Filters, ",") != m.artworkFilters {
+			m.clearAnimationCache()
+			m.resetArtworkState()
+			return m, tea.Batch(watch, m.fetchSongData())
 		}
 
-		// If vinyl_frames changed, regenerate cache
-		if cfg.Artwork.VinylMode && len(m.vinylFrameCache) > 0 && m.vinylCachedFrames != cfg.Artwork.VinylFrames {
-			m.vinylFrameCache = nil
-			m.vinylCacheTrackID = ""
-			m.vinylCachedFrames = 0
-			m.vinylRotation = 0
-			m.vinylAccumulator = 0
+		// If the animation was turned off or switched, clear cache and reload
+		// the artwork (static, or the new animation's first frame)
+		anim := activeAnimation(cfg)
+		if m.animCacheTrackID != "" && m.animCachedName != m.animationKey(cfg) {
+			m.clearAnimationCache()
+			m.resetArtworkState() // Force re-fetch and re-encode of the artwork
+			return m, tea.Batch(watch, m.fetchSongData())
+		}
+
+		// If the frame limit, the memory budget or the playback method changed
+		// (or the loop duration baked into a terminal animation), regenerate cache
+		if anim != nil && m.animCacheTrackID != "" &&
+			(m.animCachedFrames != anim.maxFrames(cfg) ||
+				m.animCachedBudget != cfg.Artwork.VinylMemoryMB ||
+				(m.animationReady() && m.animNative != m.useNativeAnimation(cfg)) ||
+				(m.animNative && m.animCachedCycle != anim.cycle(cfg))) {
+			m.clearAnimationCache()
 			if len(m.rawArtworkData) > 0 && m.lastTrackID != "" {
-				return m, tea.Batch(watchConfigCmd(), generateVinylFramesCmd(m.rawArtworkData, m.lastTrackID, cfg.Artwork.VinylFrames))
+				return m, tea.Batch(watch, m.startAnimationFrames(cfg))
 			}
 		}
 
-		// If vinyl mode was enabled, generate frames
-		if cfg.Artwork.VinylMode && len(m.vinylFrameCache) == 0 && len(m.rawArtworkData) > 0 && m.lastTrackID != "" {
-			m.vinylCacheTrackID = ""
-			return m, tea.Batch(watchConfigCmd(), generateVinylFramesCmd(m.rawArtworkData, m.lastTrackID, cfg.Artwork.VinylFrames))
+		// If an animation was enabled, generate frames (unless already underway)
+		if (anim != nil || m.coverActive(cfg)) && m.animCacheTrackID != m.lastTrackID && len(m.rawArtworkData) > 0 && m.lastTrackID != "" {
+			return m, tea.Batch(watch, m.startAnimationFrames(cfg))
 		}
 
 		if !cfg.Artwork.Enabled && m.artworkEncoded != "" {
@@ -439,55 +870,40 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else if cfg.Artwork.Enabled && m.artworkEncoded == "" && m.supportsKitty {
 			// Artwork was just enabled, reset state and fetch it for the current song
 			m.resetArtworkState()
-			return m, tea.Batch(watchConfigCmd(), m.fetchSongData())
+			return m, tea.Batch(watch, m.fetchSongData())
 		}
 		// Continue watching for more config changes
-		return m, watchConfigCmd()
+		return m, watch
 
 	case tickMsg:
-		// UI refresh tick - advance scroll animation slowly
-		m.scrollTick++
+		// UI refresh tick
 		cfg := config.Get()
 
-		// Update vinyl rotation if enabled
-		m.updateVinylRotation(cfg)
+		// Advance the artwork animation if enabled
+		m.updateAnimationFrame(cfg)
+		m.updateColorFade(cfg)
+		m.expireToast(time.Now())
 
-		// Text scrolling - only if text doesn't fit on screen
-		maxLen := cfg.Text.MaxLengthWithArt
-		if !m.supportsKitty || !cfg.Artwork.Enabled {
-			maxLen = cfg.Text.MaxLengthNoArt
-		}
+		// Text scrolling - only if text doesn't fit the layout
+		lay := m.layout(cfg)
+		maxLen := lay.textWidth
 
-		// Calculate the longest text length to determine if scrolling is needed
-		longestLen := len([]rune(m.songData.Title))
-		if l := len([]rune(m.songData.Artist)); l > longestLen {
-			longestLen = l
+		// Each line (ui.lines) scrolls on its own, only while it's wider
+		// than the room it has
+		var texts []string
+		for _, line := range m.renderLines(cfg) {
+			texts = append(texts, line.text)
 		}
-		if l := len([]rune(m.songData.Album)); l > longestLen {
-			longestLen = l
+		if lay.name == layoutMini {
+			// Mini scrolls one combined line instead
+			texts = []string{m.miniText()}
 		}
+		m.updateScrolls(cfg, time.Time(msg), texts, maxLen, m.overflow(cfg, lay))
+		// Move the tonearm along with playback progress
+		tonearmCmd := m.updateTonearm(cfg)
 
-		// Only scroll if text is longer than max length
-		if longestLen > maxLen {
-			if m.scrollPause > 0 {
-				m.scrollPause--
-			} else if m.scrollTick%scrollInterval == 0 { // Scroll every 3rd tick (interval depends on adaptive tick rate)
-				m.scrollOffset++
-
-				// Check if we've completed a full loop - pause at the end
-				loopPoint := longestLen + scrollSeparatorLen // Text length + separator length (" • ")
-				if m.scrollOffset >= loopPoint {
-					m.scrollOffset = 0
-					m.scrollPause = scrollPauseTicks // Pause for 30 ticks before restarting scroll (3s when playing, 15s when paused, 30s when idle)
-				}
-			}
-		} else {
-			// Text fits, no scrolling needed - reset offset
-			m.scrollOffset = 0
-			m.scrollPause = 0
-		}
 		// Schedule next tick with adaptive rate
-		return m, m.tickCmd()
+		return m, tea.Batch(m.tickCmd(), m.artworkUploadedCmd(), tonearmCmd)
 
 	case fetchMsg:
 		// Data fetch tick - get fresh data and schedule next fetch
@@ -505,16 +921,24 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// Say when metadata starts coming from somewhere else (another
+		// player, or MediaRemote falling back to AppleScript)
+		if msg.source != m.source {
+			if m.source != "" && msg.source != "" {
+				m.notify("Source: " + msg.source)
+			}
+			m.source = msg.source
+		}
+
 		// Reset scroll when track changes
 		trackID := fmt.Sprintf("%s|%s", msg.title, msg.artist)
 		if trackID != m.lastTrackID {
-			m.scrollOffset = 0
-			m.scrollPause = scrollPauseTicks
-			m.scrollTick = 0
+			m.scrolls = nil
 
-			// Clear vinyl cache so old artwork doesn't keep spinning
-			m.vinylFrameCache = nil
-			m.vinylCacheTrackID = ""
+			// Clear animation cache so old artwork doesn't keep playing, and
+			// stop processing the previous track's artwork
+			m.clearAnimationCache()
+			m.cancelArtworkProcessing()
 			m.lastTrackID = trackID
 
 			// New track: forget the old artwork hash so the fetch loop keeps
@@ -527,6 +951,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.songData.Artist = msg.artist
 		m.songData.Album = msg.album
 		m.songData.Status = msg.status
+		m.songData.Year = msg.year
 		m.songData.TotalTime = formatTime(msg.duration)
 
 		// Update tracking info for smooth interpolation
@@ -539,53 +964,132 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Handle artwork: re-process only when the actual image data changes (by hash)
 		if msg.artworkHash != 0 && msg.artworkHash != m.lastArtworkHash {
 			m.lastArtworkHash = msg.artworkHash
-			m.rawArtworkData = msg.rawArtwork
-
-			// Process artwork (decode, encode for Kitty, extract color)
-			shouldExtractColor := cfg.UI.ColorMode == "auto"
-			func() {
-				// Malformed images can panic inside image decoders; artwork
-				// must never crash the UI, so swallow and keep the old art
-				defer func() {
-					_ = recover()
-				}()
-				color, encoded, err := processArtwork(msg.rawArtwork, shouldExtractColor, m.vinylRotation, cfg.Artwork.VinylFrames)
-				if err == nil {
-					if encoded != "" {
-						m.artworkEncoded = encoded
-					}
-					if shouldExtractColor && color != "" {
-						m.color = color
-					}
-				}
-			}()
+			// Decode, encode and extract color in the background; the current
+			// artwork stays up until artworkReadyMsg arrives
+			return m, tea.Batch(m.processArtworkCmd(cfg, msg.rawArtwork, msg.artworkHash), m.artworkUploadedCmd())
+		}
+
+		return m, m.artworkUploadedCmd()
 
-			// Generate vinyl frames for new artwork
-			if cfg.Artwork.VinylMode && trackID != m.vinylCacheTrackID {
-				m.vinylCacheTrackID = trackID
-				return m, generateVinylFramesCmd(msg.rawArtwork, trackID, cfg.Artwork.VinylFrames)
+	case artworkReadyMsg:
+		// Artwork processed in background - drop it if the track or artwork
+		// has moved on since
+		if msg.trackID != m.lastTrackID || msg.artworkHash != m.lastArtworkHash {
+			return m, nil
+		}
+		m.artworkCancel = nil
+		if msg.err != nil {
+			return m, nil
+		}
+		cfg := config.Get()
+		previousArtwork := m.rawArtworkData
+		m.rawArtworkData = msg.rawArtwork
+		var transitionCmd tea.Cmd
+
+		if msg.encoded != "" {
+			// Transition from the cover on screen, unless it's the same one
+			if cfg.Artwork.Transition != transitionNone && m.artworkEncoded != "" &&
+				len(previousArtwork) > 0 && hashBytes(previousArtwork) != msg.artworkHash {
+				transitionCmd = m.startTransition(cfg, previousArtwork, msg.encoded)
+			} else {
+				m.presentArtwork(msg.encoded)
 			}
+			m.artworkFilters = msg.filters
+			// Earlier covers' file-mode uploads have been superseded; the
+			// tonearm, transitions and animation jobs release their own
+			kittyFiles.release(kittyOwnerStill, msg.encoded)
+		}
+		if msg.colors.accent != "" && cfg.UI.ColorMode == "auto" {
+			m.fadeColorTo(msg.colors.accent, cfg)
+			m.palette = msg.colors
+			m.paletteStrategy = msg.strategy
+			m.paletteBackground = msg.background
 		}
 
-		return m, nil
+		// Generate animation frames for the new artwork (or play it, if it's
+		// an animated cover)
+		m.animatedCover = isAnimatedArtwork(msg.rawArtwork)
+		if activeAnimation(cfg) != nil || m.coverActive(cfg) {
+			return m, tea.Batch(m.startAnimationFrames(cfg), m.artworkUploadedCmd(), transitionCmd)
+		}
+		return m, tea.Batch(m.artworkUploadedCmd(), transitionCmd)
 
-	case vinylFramesMsg:
-		// Vinyl frames generated in background - cache them if for current track
-		if msg.trackID == m.lastTrackID && len(msg.frames) > 0 {
-			m.vinylFrameCache = msg.frames
-			m.vinylCachedFrames = len(msg.frames) // Store frame count to detect config changes
-			// Start from frame 0
-			m.vinylRotation = 0
-			m.vinylAccumulator = 0
-			// Display first frame immediately
-			m.artworkEncoded = msg.frames[0]
+	case paletteMsg:
+		// Palette extracted again in background - drop it if the artwork has
+		// moved on since, or auto color mode was turned off
+		cfg := config.Get()
+		if msg.trackID != m.lastTrackID || msg.artworkHash != hashBytes(m.rawArtworkData) || cfg.UI.ColorMode != "auto" {
+			return m, nil
 		}
+		m.fadeColorTo(msg.colors.accent, cfg)
+		m.palette = msg.colors
+		m.paletteStrategy = msg.strategy
+		m.paletteBackground = msg.background
 		return m, nil
 
+	case animationFrameMsg:
+		// An animation frame rendered in background - cache it if for current job
+		if msg.job != m.animJob || msg.trackID != m.lastTrackID {
+			return m, nil
+		}
+		if len(m.animFrameCache) != msg.total {
+			m.animFrameCache = make([]string, msg.total)
+			m.animNative = false
+		}
+		m.animFrameCache[msg.index] = msg.frame
+		m.animCycle = msg.cycle
+
+		// Start spinning at a coarse step as soon as enough evenly spaced
+		// frames exist, and refine the step as the rest arrive
+		wasReady := m.animStep > 0
+		m.animStep = frameCoarseStep(m.animFrameCache)
+		if !wasReady && m.animStep > 0 {
+			// Start from frame 0, displayed immediately
+			m.animFrame = 0
+			m.animAccumulator = 0
+			m.presentArtwork(m.animFrameCache[0])
+		}
+		return m, tea.Batch(waitAnimationFrameCmd(msg.ch), m.artworkUploadedCmd())
+
+	case animationUploadMsg:
+		// Frames uploaded as a Kitty animation - the terminal spins the record
+		// from here on, View only starts/stops it
+		if msg.job == m.animJob && msg.trackID == m.lastTrackID && msg.upload != "" {
+			m.animFrameCache = nil
+			m.animStep = 0
+			m.animNative = true
+			m.animCachedCycle = msg.cycle
+			m.animCycle = msg.cycle
+			m.presentArtwork(msg.upload)
+		}
+		return m, m.artworkUploadedCmd()
+
+	case transitionMsg:
+		// Cover transition frames rendered - play them, then the new cover
+		if msg.job != m.transitionJob || !m.transitioning {
+			return m, nil
+		}
+		m.transitionFrames = msg.frames
+		if m.stepTransition() {
+			return m, tea.Batch(transitionStepCmd(msg.job, config.Get()), m.artworkUploadedCmd())
+		}
+		return m, m.artworkUploadedCmd()
+
+	case transitionStepMsg:
+		if msg.job != m.transitionJob || !m.transitioning {
+			return m, nil
+		}
+		if m.stepTransition() {
+			return m, tea.Batch(transitionStepCmd(msg.job, config.Get()), m.artworkUploadedCmd())
+		}
+		return m, m.artworkUploadedCmd()
+
 	case controlMsg:
-		// Result of a background playback control action
-		if msg.err != nil {
-			m.lastError = msg.err
+		// Result of a background playback control action. A failure is a
+		// toast: the card still shows what's playing. One the player doesn't
+		// support is just a no-op.
+		if msg.err != nil && !errors.Is(msg.err, ErrUnsupported) {
+			m.notifyError(msg.err)
 		}
 		return m, nil
 
@@ -593,6 +1097,37 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Clear the flag after one render cycle
 		m.forceDeleteImg = false
 		return m, nil
+
+	case tonearmMsg:
+		// Tonearm overlay rendered in background - drop it if progress has
+		// already asked for a different angle
+		if msg.angle == m.tonearmRequested {
+			m.tonearmEncoded = msg.encoded
+			m.tonearmAngle = msg.angle
+			m.tonearmUploadPending = true
+			// Earlier angles' files (including stale ones) are superseded
+			kittyFiles.release(kittyOwnerTonearm, msg.encoded)
+			m.tonearmUploadSeq++
+			seq := m.tonearmUploadSeq
+			return m, tea.Tick(kittyUploadFlushDelay, func(time.Time) tea.Msg {
+				return tonearmUploadedMsg{seq: seq}
+			})
+		}
+		return m, nil
+
+	case tonearmUploadedMsg:
+		if msg.seq == m.tonearmUploadSeq {
+			m.tonearmUploadPending = false
+		}
+		return m, nil
+
+	case artworkUploadedMsg:
+		// Only the latest upload counts — an older ack must not cancel a newer
+		// pending upload (animation frames change faster than the flush delay)
+		if msg.seq == m.artworkUploadSeq {
+			m.artworkUploadPending = false
+		}
+		return m, nil
 	}
 
 	return m, nil
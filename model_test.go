@@ -1,23 +1,36 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"image/color"
+	"image/png"
 	"math"
 	"testing"
 	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
-// TestVinylTickRate verifies frame-rate-synced tick calculation and clamping
-func TestVinylTickRate(t *testing.T) {
+// TestAnimationTickRate verifies frame-rate-synced tick calculation and clamping
+func TestAnimationTickRate(t *testing.T) {
 	makeCfg := func(rpm float64, frames int) Config {
 		cfg := Config{}
+		cfg.Artwork.Animation = "vinyl"
 		cfg.Artwork.VinylRPM = rpm
 		cfg.Artwork.VinylFrames = frames
 		return cfg
 	}
+	// The rate for the animation's full frame count
+	animationTickRate := func(cfg Config) time.Duration {
+		anim := activeAnimation(cfg)
+		return frameTickRate(anim.cycle(cfg), anim.maxFrames(cfg))
+	}
 
 	t.Run("default config (10 RPM, 90 frames)", func(t *testing.T) {
 		// 10 RPM / 60 * 90 = 15 fps → ~66ms per frame
-		got := vinylTickRate(makeCfg(10, 90))
+		got := animationTickRate(makeCfg(10, 90))
 		want := 66 * time.Millisecond
 		if got != want {
 			t.Errorf("got %v, want %v", got, want)
@@ -26,27 +39,27 @@ func TestVinylTickRate(t *testing.T) {
 
 	t.Run("clamps to 50ms minimum", func(t *testing.T) {
 		// 100 RPM / 60 * 90 = 150 fps → 6.7ms, clamped to 50ms
-		if got := vinylTickRate(makeCfg(100, 90)); got != 50*time.Millisecond {
+		if got := animationTickRate(makeCfg(100, 90)); got != 50*time.Millisecond {
 			t.Errorf("got %v, want 50ms", got)
 		}
 	})
 
 	t.Run("clamps to 300ms maximum", func(t *testing.T) {
 		// 1 RPM / 60 * 45 = 0.75 fps → 1333ms, clamped to 300ms
-		if got := vinylTickRate(makeCfg(1, 45)); got != 300*time.Millisecond {
+		if got := animationTickRate(makeCfg(1, 45)); got != 300*time.Millisecond {
 			t.Errorf("got %v, want 300ms", got)
 		}
 	})
 
 	t.Run("zero for invalid config", func(t *testing.T) {
-		if got := vinylTickRate(makeCfg(0, 90)); got != 0 {
+		if got := animationTickRate(makeCfg(0, 90)); got != 0 {
 			t.Errorf("got %v, want 0", got)
 		}
 	})
 }
 
 // TestVinylRotationSpeed is a regression test for the vinyl spinning at the
-// wrong RPM: updateVinylRotation used a hardcoded 100ms tick duration while
+// wrong RPM: updateAnimationFrame used a hardcoded 100ms tick duration while
 // tickCmd synced ticks to the frame rate, making the record spin ~50% fast at
 // default settings. Simulate one second of ticks and check frames advanced.
 func TestVinylRotationSpeed(t *testing.T) {
@@ -57,20 +70,22 @@ func TestVinylRotationSpeed(t *testing.T) {
 	config.Set(cfg)
 
 	m := &model{
-		isPlaying:       true,
-		vinylFrameCache: make([]string, cfg.Artwork.VinylFrames),
+		isPlaying:      true,
+		animFrameCache: make([]string, cfg.Artwork.VinylFrames),
+		animStep:       1, // All frames rendered
 	}
 
 	// Simulate 1 second of wall time at the actual tick rate
-	tickRate := vinylTickRate(cfg)
+	anim := activeAnimation(cfg)
+	tickRate := frameTickRate(anim.cycle(cfg), anim.maxFrames(cfg))
 	ticks := int(time.Second / tickRate)
 	framesAdvanced := 0
-	prev := m.vinylRotation
+	prev := m.animFrame
 	for i := 0; i < ticks; i++ {
-		m.updateVinylRotation(cfg)
-		diff := (m.vinylRotation - prev + cfg.Artwork.VinylFrames) % cfg.Artwork.VinylFrames
+		m.updateAnimationFrame(cfg)
+		diff := (m.animFrame - prev + cfg.Artwork.VinylFrames) % cfg.Artwork.VinylFrames
 		framesAdvanced += diff
-		prev = m.vinylRotation
+		prev = m.animFrame
 	}
 
 	// Expected: RPM/60 * frames = 15 frames per second (allow rounding slack)
@@ -79,3 +94,304 @@ func TestVinylRotationSpeed(t *testing.T) {
 		t.Errorf("advanced %d frames in 1s of ticks, want ~%.1f", framesAdvanced, want)
 	}
 }
+
+// TestAnimationFrameGap verifies the per-frame gap used for terminal-side vinyl
+// animation matches the configured RPM
+func TestAnimationFrameGap(t *testing.T) {
+	cfg := Config{}
+	cfg.Artwork.Animation = "vinyl"
+	cfg.Artwork.VinylRPM = 10
+	cfg.Artwork.VinylFrames = 90
+
+	// 10 RPM / 60 * 90 = 15 fps → ~67ms per frame
+	if got := frameGapMs(activeAnimation(cfg).cycle(cfg), cfg.Artwork.VinylFrames); got != 67 {
+		t.Errorf("got %dms, want 67ms", got)
+	}
+
+	cfg.Artwork.VinylRPM = 0
+	if got := frameGapMs(activeAnimation(cfg).cycle(cfg), cfg.Artwork.VinylFrames); got != 0 {
+		t.Errorf("got %dms for invalid config, want 0", got)
+	}
+}
+
+// TestUseNativeAnimation verifies vinyl_playback selects between terminal-side
+// animation and frame swapping, falling back for terminals without support
+func TestUseNativeAnimation(t *testing.T) {
+	tests := []struct {
+		playback  string
+		supported bool
+		want      bool
+	}{
+		{"auto", true, true},
+		{"auto", false, false},
+		{"terminal", false, true},
+		{"frames", true, false},
+	}
+
+	for _, tt := range tests {
+		cfg := Config{}
+		cfg.Artwork.VinylPlayback = tt.playback
+		m := model{supportsKittyAnim: tt.supported}
+		if got := m.useNativeAnimation(cfg); got != tt.want {
+			t.Errorf("vinyl_playback=%s supported=%v: got %v, want %v", tt.playback, tt.supported, got, tt.want)
+		}
+	}
+}
+
+// TestFrameStepChain verifies coarse-to-fine steps nest and end at 1
+func TestFrameStepChain(t *testing.T) {
+	tests := []struct {
+		total int
+		want  []int
+	}{
+		{90, []int{10, 5, 1}},
+		{45, []int{5, 1}},
+		{64, []int{8, 4, 2, 1}},
+		{8, []int{1}},
+		{7, []int{1}},
+	}
+
+	for _, tt := range tests {
+		got := frameStepChain(tt.total)
+		if len(got) != len(tt.want) {
+			t.Errorf("frameStepChain(%d) = %v, want %v", tt.total, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("frameStepChain(%d) = %v, want %v", tt.total, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+// TestFrameRenderOrder verifies every frame is rendered exactly once, with
+// the coarse evenly spaced frames first
+func TestFrameRenderOrder(t *testing.T) {
+	order := frameRenderOrder(90)
+	if len(order) != 90 {
+		t.Fatalf("got %d frames, want 90", len(order))
+	}
+
+	seen := make(map[int]bool)
+	for _, i := range order {
+		if seen[i] {
+			t.Fatalf("frame %d rendered twice", i)
+		}
+		seen[i] = true
+	}
+
+	// First 9 frames are every 10th frame (40° apart)
+	for n, i := range order[:9] {
+		if i != n*10 {
+			t.Errorf("order[%d] = %d, want %d", n, i, n*10)
+		}
+	}
+}
+
+// TestFrameCoarseStep verifies animation can start once evenly spaced frames
+// exist and refines as more arrive
+func TestFrameCoarseStep(t *testing.T) {
+	frames := make([]string, 90)
+	if got := frameCoarseStep(frames); got != 0 {
+		t.Errorf("empty cache: got step %d, want 0", got)
+	}
+
+	for _, i := range frameRenderOrder(90)[:9] {
+		frames[i] = "frame"
+	}
+	if got := frameCoarseStep(frames); got != 10 {
+		t.Errorf("coarse frames: got step %d, want 10", got)
+	}
+
+	for i := range frames {
+		frames[i] = "frame"
+	}
+	if got := frameCoarseStep(frames); got != 1 {
+		t.Errorf("full cache: got step %d, want 1", got)
+	}
+}
+
+// TestAnimationFrameBudget verifies the memory cap limits the frame count
+func TestAnimationFrameBudget(t *testing.T) {
+	// 1MB frames, 64MB budget, 90 max → 64 frames
+	if got := animationFrameBudget(1024*1024, 90, 64); got != 64 {
+		t.Errorf("got %d frames, want 64", got)
+	}
+	// Small frames are capped at vinyl_frames
+	if got := animationFrameBudget(1024, 90, 64); got != 90 {
+		t.Errorf("got %d frames, want 90", got)
+	}
+	// Tiny budget still spins
+	if got := animationFrameBudget(1024*1024, 90, 1); got != animMinFrames {
+		t.Errorf("got %d frames, want %d", got, animMinFrames)
+	}
+}
+
+// TestRenderAnimationFrames verifies every frame is delivered progressively and
+// that cancelling stops the job
+func TestRenderAnimationFrames(t *testing.T) {
+	cfg := Config{}
+	cfg.Artwork.WidthPixels = 40
+	cfg.Artwork.VinylMode = true
+	cfg.Artwork.VinylFrames = 16
+	cfg.Artwork.VinylMemoryMB = 64
+	cfg.Artwork.Transmission = kittyTransmitDirect
+
+	img := generateGradientImage(40, 40, color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("Failed to encode test image: %v", err)
+	}
+
+	t.Run("delivers all frames", func(t *testing.T) {
+		ch := make(chan tea.Msg, cfg.Artwork.VinylFrames+1)
+		go renderAnimationFrames(context.Background(), ch, 1, "a|b", buf.Bytes(), cfg, false)
+
+		seen := make(map[int]bool)
+		for msg := range ch {
+			frame, ok := msg.(animationFrameMsg)
+			if !ok || frame.frame == "" || frame.total != 16 {
+				t.Fatalf("unexpected message %#v", msg)
+			}
+			seen[frame.index] = true
+		}
+		if len(seen) != 16 {
+			t.Errorf("got %d distinct frames, want 16", len(seen))
+		}
+	})
+
+	t.Run("stops when cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		ch := make(chan tea.Msg)
+		go renderAnimationFrames(ctx, ch, 1, "a|b", buf.Bytes(), cfg, false)
+
+		// Nobody reads the channel; the job must still finish and close it
+		select {
+		case _, ok := <-ch:
+			for ok {
+				_, ok = <-ch
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("cancelled job didn't finish")
+		}
+	})
+}
+
+// TestAnimationFramesFromStaleJobDropped verifies a track change cancels
+// in-flight frame generation and its late results are ignored
+func TestAnimationFramesFromStaleJobDropped(t *testing.T) {
+	cfg := Config{}
+	cfg.Artwork.Animation = "vinyl"
+	cfg.Artwork.VinylFrames = 90
+	cfg.Artwork.VinylMemoryMB = 64
+
+	m := model{lastTrackID: "a|b"}
+	_ = m.startAnimationFrames(cfg)
+	job := m.animJob
+
+	m.clearAnimationCache()
+	if m.animCancel != nil {
+		t.Error("Expected in-flight job to be cancelled")
+	}
+
+	_ = m.startAnimationFrames(cfg)
+	updated, _ := m.Update(animationFrameMsg{job: job, trackID: "a|b", index: 0, total: 90, frame: "x"})
+	if len(updated.(model).animFrameCache) != 0 {
+		t.Error("Expected frame from a stale job to be dropped")
+	}
+}
+
+// TestArtworkProcessedInBackground verifies artwork is processed by a command
+// and results for an older track or artwork are dropped
+func TestArtworkProcessedInBackground(t *testing.T) {
+	var buf bytes.Buffer
+	assertNoError(t, png.Encode(&buf, generateTestImage(16, 16, color.RGBA{200, 40, 40, 255})))
+	raw := buf.Bytes()
+	hash := hashBytes(raw)
+
+	cfg := Config{}
+	cfg.UI.ColorMode = "auto"
+	cfg.Artwork.WidthPixels = 16
+
+	m := model{lastTrackID: "a|b", lastArtworkHash: hash}
+	cmd := m.processArtworkCmd(cfg, raw, hash)
+	ready, ok := cmd().(artworkReadyMsg)
+	if !ok {
+		t.Fatal("Expected an artworkReadyMsg")
+	}
+	assertNoError(t, ready.err)
+	if ready.trackID != "a|b" || ready.artworkHash != hash || ready.encoded == "" {
+		t.Errorf("Unexpected result: track %q, hash %d, encoded %d bytes", ready.trackID, ready.artworkHash, len(ready.encoded))
+	}
+
+	// A new track cancels the work and its result never arrives
+	cmd = m.processArtworkCmd(cfg, raw, hash)
+	m.cancelArtworkProcessing()
+	if msg := cmd(); msg != nil {
+		t.Errorf("Expected cancelled processing to return nothing, got %T", msg)
+	}
+
+	stale := ready
+	stale.trackID = "c|d"
+	updated, _ := m.Update(stale)
+	if updated.(model).artworkEncoded != "" {
+		t.Error("Expected artwork for a previous track to be dropped")
+	}
+
+	m.lastArtworkHash = hash + 1
+	updated, _ = m.Update(ready)
+	if updated.(model).artworkEncoded != "" {
+		t.Error("Expected superseded artwork to be dropped")
+	}
+
+	// Malformed artwork reports an error instead of crashing
+	ready = m.processArtworkCmd(cfg, []byte("not an image"), 1)().(artworkReadyMsg)
+	assertError(t, ready.err, "undecodable artwork")
+}
+
+// TestPaletteChangeKeepsArtwork verifies a new ui.palette extracts the
+// palette again in the background, keeping the artwork and its frames
+func TestPaletteChangeKeepsArtwork(t *testing.T) {
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+	defer config.Set(config.Get())
+	var buf bytes.Buffer
+	assertNoError(t, png.Encode(&buf, generateGradientImage(16, 16, color.RGBA{200, 40, 40, 255}, color.RGBA{40, 40, 200, 255})))
+
+	cfg := layoutTestConfig(layoutCard)
+	cfg.UI.ColorMode = "auto"
+	cfg.UI.Palette = "muted"
+	config.Set(cfg)
+	m := model{
+		lastTrackID:       "a|b",
+		rawArtworkData:    buf.Bytes(),
+		artworkEncoded:    "encoded",
+		animCacheTrackID:  "a|b",
+		animFrameCache:    []string{"frame"},
+		palette:           palette{accent: "#ff0000"},
+		paletteStrategy:   "vibrant",
+		paletteBackground: backgroundFor(cfg),
+	}
+	m.animCachedName = m.animationKey(cfg)
+
+	updated, cmd := m.Update(configReloadMsg{})
+	m = updated.(model)
+	if cmd == nil || m.artworkEncoded != "encoded" || len(m.animFrameCache) != 1 {
+		t.Fatal("palette change reset the artwork")
+	}
+	assertEqual(t, m.paletteStrategy, "muted", "strategy")
+
+	msg, ok := m.paletteCmd(cfg)().(paletteMsg)
+	if !ok {
+		t.Fatal("Expected a paletteMsg")
+	}
+	updated, _ = m.Update(msg)
+	assertEqual(t, updated.(model).palette, msg.colors, "palette applied")
+
+	// Not for artwork that has changed since
+	m.rawArtworkData = []byte("other")
+	updated, _ = m.Update(paletteMsg{trackID: "a|b", colors: palette{accent: "#00ff00"}})
+	assertEqual(t, updated.(model).palette, m.palette, "stale palette dropped")
+}
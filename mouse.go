@@ -0,0 +1,166 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// volumeStep is how much one scroll wheel notch changes the volume
+const volumeStep = 0.05
+
+// mouseEnabled is whether the mouse is captured: ui.mouse, except inline,
+// where the UI's rows on screen aren't known
+func mouseEnabled(cfg Config) bool {
+	return cfg.UI.Mouse && !cfg.UI.Inline
+}
+
+// region is a rectangle of terminal cells
+type region struct {
+	x, y, w, h int
+}
+
+func (r region) contains(x, y int) bool {
+	return x >= r.x && x < r.x+r.w && y >= r.y && y < r.y+r.h
+}
+
+// button is a clickable transport control
+type button struct {
+	region
+	action string // Control() command
+}
+
+// hitMap is where the clickable parts of the rendered UI are on screen
+type hitMap struct {
+	card    region // Scroll for volume
+	bar     region // Click to seek, hover for the time
+	buttons []button
+}
+
+// controls renders the transport buttons under the box, and where each
+// sits in the row. The none icon set still needs something to click, so
+// it gets the ASCII glyphs.
+func (m model) controls(styles themeStyles, icons iconSet) (string, []button) {
+	if icons.width() == 0 {
+		icons = iconSets["ascii"]
+	}
+	playPause := icons.pause
+	if !m.isPlaying {
+		playPause = icons.play
+	}
+
+	var row strings.Builder
+	var buttons []button
+	for i, b := range []struct{ glyph, action string }{
+		{icons.previous, "previous"},
+		{playPause, "play-pause"},
+		{icons.next, "next"},
+	} {
+		if i > 0 {
+			row.WriteString("   ")
+		}
+		x := lipgloss.Width(row.String())
+		row.WriteString(styles.title.Render(b.glyph))
+		buttons = append(buttons, button{region{x, 0, lipgloss.Width(b.glyph), 1}, b.action})
+	}
+	return row.String(), buttons
+}
+
+// centerOffset is where lipgloss.JoinVertical puts a line w wide in a
+// block total wide when centering (the odd cell goes on the left)
+func centerOffset(total, w int) int {
+	return max(total-w+1, 0) / 2
+}
+
+// placeOffset is where place puts the rendered UI on screen (lipgloss.Place
+// gives the odd cell to the right and bottom)
+func (m model) placeOffset(cfg Config, ui string, hPos lipgloss.Position) (x, y int) {
+	if cfg.UI.Inline {
+		return 0, 0
+	}
+	if hPos == lipgloss.Center {
+		x = max(m.width-lipgloss.Width(ui), 0) / 2
+	}
+	return x, max(m.height-lipgloss.Height(ui), 0) / 2
+}
+
+// handleMouse seeks, presses buttons, changes the volume and tracks the
+// pointer over the progress bar
+func (m model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	hits := m.lastHits()
+
+	m.hoverBar = hits.bar.contains(msg.X, msg.Y) && m.duration > 0
+	if m.hoverBar {
+		m.hoverPosition = barFraction(hits.bar, msg.X) * float64(m.duration)
+	}
+
+	if msg.Action == tea.MouseActionPress {
+		switch msg.Button {
+		case tea.MouseButtonLeft:
+			if m.hoverBar {
+				return m.seek(m.hoverPosition)
+			}
+			for _, b := range hits.buttons {
+				if b.contains(msg.X, msg.Y) {
+					return m, m.controlCmd(b.action)
+				}
+			}
+		case tea.MouseButtonWheelUp, tea.MouseButtonWheelDown:
+			if hits.card.contains(msg.X, msg.Y) {
+				delta := volumeStep
+				if msg.Button == tea.MouseButtonWheelDown {
+					delta = -volumeStep
+				}
+				return m, m.volumeCmd(delta)
+			}
+		}
+	}
+	return m, nil
+}
+
+// lastHits is where the last View put the clickable parts. Motion events
+// come in fast, so they use that rather than drawing the UI again (a model
+// without one, as in tests, draws it).
+func (m model) lastHits() hitMap {
+	if m.hits != nil {
+		return *m.hits
+	}
+	_, hits := m.render(config.Get())
+	return hits
+}
+
+// barFraction is how far along the bar column x is, by the middle of the
+// cell
+func barFraction(bar region, x int) float64 {
+	return clamp01((float64(x-bar.x) + 0.5) / float64(bar.w))
+}
+
+// seek jumps to seconds if the controller can, moving the bar right away
+// rather than on the next fetch
+func (m model) seek(seconds float64) (tea.Model, tea.Cmd) {
+	seeker, ok := m.mediaController.(Seeker)
+	if !ok {
+		return m, nil
+	}
+	m.lastPosition = seconds
+	m.lastPositionTime = time.Now()
+	return m, tea.Sequence(
+		func() tea.Msg {
+			return controlMsg{err: seeker.Seek(seconds)}
+		},
+		m.fetchSongData(),
+	)
+}
+
+// volumeCmd changes the volume in the background if the controller can
+func (m model) volumeCmd(delta float64) tea.Cmd {
+	volume, ok := m.mediaController.(VolumeController)
+	if !ok {
+		return nil
+	}
+	return func() tea.Msg {
+		return controlMsg{err: volume.ChangeVolume(delta)}
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fakeController can seek, and records volume changes and levels
+type fakeController struct {
+	volumes  []float64
+	levels   []float64
+	controls []string
+}
+
+func (f *fakeController) GetMetadata() (title, artist, album, status string, err error) {
+	return "Title", "Artist", "Album", "Playing", nil
+}
+func (f *fakeController) GetDuration() (int64, error)   { return 200, nil }
+func (f *fakeController) GetPosition() (float64, error) { return 0, nil }
+func (f *fakeController) GetArtwork() ([]byte, error)   { return nil, nil }
+func (f *fakeController) Seek(seconds float64) error    { return nil }
+func (f *fakeController) Control(command string) error {
+	f.controls = append(f.controls, command)
+	return nil
+}
+func (f *fakeController) ChangeVolume(delta float64) error {
+	f.volumes = append(f.volumes, delta)
+	return nil
+}
+func (f *fakeController) SetVolume(level float64) error {
+	f.levels = append(f.levels, level)
+	return nil
+}
+
+// basicController can't seek or change the volume
+type basicController struct{}
+
+func (basicController) GetMetadata() (title, artist, album, status string, err error) {
+	return "Title", "Artist", "Album", "Playing", nil
+}
+func (basicController) GetDuration() (int64, error)   { return 200, nil }
+func (basicController) GetPosition() (float64, error) { return 0, nil }
+func (basicController) Control(command string) error  { return nil }
+func (basicController) GetArtwork() ([]byte, error)   { return nil, nil }
+
+// cellsAt is the text in cells x to x+w of a rendered row
+func cellsAt(row string, x, w int) string {
+	var cells strings.Builder
+	col := 0
+	for _, cluster := range graphemes(row) {
+		if col >= x && col < x+w {
+			cells.WriteString(cluster)
+		}
+		col += cellWidth(cluster)
+	}
+	return cells.String()
+}
+
+func mouseTestModel(t *testing.T, name string) (model, *fakeController) {
+	t.Helper()
+	cfg := layoutTestConfig(name)
+	cfg.UI.Mouse = true
+	config.Set(cfg)
+
+	controller := &fakeController{}
+	m := model{width: 80, height: 30, duration: 200, lastPosition: 50, mediaController: controller}
+	m.songData = SongData{Title: "Title", Artist: "Artist", Album: "Album", Status: "Paused"}
+	return m, controller
+}
+
+// TestHitMap verifies the hit regions land on what's drawn there
+func TestHitMap(t *testing.T) {
+	defer config.Set(config.Get())
+
+	for _, name := range []string{layoutCard, layoutVertical, layoutCompact, layoutMini} {
+		m, _ := mouseTestModel(t, name)
+		if name == layoutMini {
+			m.height = 1
+		}
+		ui, hits := m.render(config.Get())
+		rows := strings.Split(ui, "\n")
+
+		bar := cellsAt(rows[hits.bar.y], hits.bar.x, hits.bar.w)
+		if hits.bar.w == 0 || strings.Trim(bar, "█─") != "" {
+			t.Errorf("%s: bar region holds %q", name, bar)
+		}
+		if !hits.card.contains(hits.bar.x, hits.bar.y) {
+			t.Errorf("%s: bar outside the card", name)
+		}
+		if name == layoutMini {
+			assertEqual(t, len(hits.buttons), 0, "mini buttons")
+			continue
+		}
+
+		icons := iconSets["nerd"]
+		want := []string{icons.previous, icons.play, icons.next}
+		assertEqual(t, len(hits.buttons), 3, name+" buttons")
+		for i, b := range hits.buttons {
+			if got := cellsAt(rows[b.y], b.x, b.w); got != want[i] {
+				t.Errorf("%s: %s button region holds %q, want %q", name, b.action, got, want[i])
+			}
+		}
+	}
+}
+
+// TestMouseSeek verifies clicking the bar seeks to that point, and hovering
+// shows the time there
+func TestMouseSeek(t *testing.T) {
+	defer config.Set(config.Get())
+	m, _ := mouseTestModel(t, layoutCard)
+	_, hits := m.render(config.Get())
+
+	// Hovering the last cell shows (nearly) the end
+	end := hits.bar.x + hits.bar.w - 1
+	updated, cmd := m.Update(tea.MouseMsg{X: end, Y: hits.bar.y, Action: tea.MouseActionMotion})
+	hovered := updated.(model)
+	if !hovered.hoverBar || hovered.hoverPosition < 190 || cmd != nil {
+		t.Errorf("hover: %v at %.1f", hovered.hoverBar, hovered.hoverPosition)
+	}
+	if !strings.Contains(hovered.View(), formatTime(int64(hovered.hoverPosition))) {
+		t.Error("hover time not shown")
+	}
+
+	// Clicking the middle seeks halfway, and the bar moves at once
+	middle := hits.bar.x + hits.bar.w/2
+	updated, cmd = hovered.Update(tea.MouseMsg{X: middle, Y: hits.bar.y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	if cmd == nil {
+		t.Fatal("click on the bar didn't seek")
+	}
+	seekTo := updated.(model).lastPosition
+	if seekTo < 90 || seekTo > 110 {
+		t.Errorf("seek to %.1f, want about 100", seekTo)
+	}
+
+	// Leaving the bar drops the hover time
+	updated, _ = updated.(model).Update(tea.MouseMsg{X: 0, Y: 0, Action: tea.MouseActionMotion})
+	assertEqual(t, updated.(model).hoverBar, false, "hover after leaving")
+}
+
+// TestMouseControls verifies buttons and the scroll wheel, and that
+// controllers without seek or volume ignore them
+func TestMouseControls(t *testing.T) {
+	defer config.Set(config.Get())
+	m, controller := mouseTestModel(t, layoutCard)
+	_, hits := m.render(config.Get())
+
+	next := hits.buttons[2]
+	_, cmd := m.Update(tea.MouseMsg{X: next.x, Y: next.y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	if cmd == nil {
+		t.Error("next button didn't run a command")
+	}
+
+	_, cmd = m.Update(tea.MouseMsg{X: hits.card.x + 1, Y: hits.card.y + 1, Action: tea.MouseActionPress, Button: tea.MouseButtonWheelDown})
+	if cmd == nil {
+		t.Fatal("scroll over the card didn't change the volume")
+	}
+	cmd()
+	assertEqual(t, len(controller.volumes), 1, "volume changes")
+	assertEqual(t, controller.volumes[0], -volumeStep, "volume down")
+
+	// Outside the card the wheel does nothing
+	_, cmd = m.Update(tea.MouseMsg{X: 0, Y: 0, Action: tea.MouseActionPress, Button: tea.MouseButtonWheelUp})
+	if cmd != nil {
+		t.Error("scroll outside the card changed the volume")
+	}
+
+	// No Seeker or VolumeController: no-ops
+	m.mediaController = basicController{}
+	_, cmd = m.Update(tea.MouseMsg{X: hits.bar.x, Y: hits.bar.y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	if cmd != nil {
+		t.Error("seek without a Seeker")
+	}
+	_, cmd = m.Update(tea.MouseMsg{X: hits.card.x + 1, Y: hits.card.y + 1, Action: tea.MouseActionPress, Button: tea.MouseButtonWheelUp})
+	if cmd != nil {
+		t.Error("volume without a VolumeController")
+	}
+}
+
+// TestMouseUsesLastView verifies mouse events go by where the last View put
+// things, without drawing the UI again
+func TestMouseUsesLastView(t *testing.T) {
+	defer config.Set(config.Get())
+	m, _ := mouseTestModel(t, layoutCard)
+	m.hits = &hitMap{}
+	_, hits := m.render(config.Get())
+	m.View()
+	assertEqual(t, m.hits.bar, hits.bar, "bar from the last view")
+
+	// The view moves, but the screen shows the last one until it's drawn
+	m.width += 20
+	if _, moved := m.render(config.Get()); moved.bar == hits.bar {
+		t.Fatal("the bar didn't move")
+	}
+	updated, _ := m.Update(tea.MouseMsg{X: hits.bar.x, Y: hits.bar.y, Action: tea.MouseActionMotion})
+	assertEqual(t, updated.(model).hoverBar, true, "hover where the bar was drawn")
+}
+
+// TestMouseUnsupported verifies a seek or volume change the player doesn't
+// support is a no-op, not an error
+func TestMouseUnsupported(t *testing.T) {
+	updated, _ := model{}.Update(controlMsg{err: ErrUnsupported})
+	assertEqual(t, updated.(model).toast.text, "", "no toast")
+}
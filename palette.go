@@ -0,0 +1,334 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sort"
+)
+
+const (
+	paletteClusters   = 6    // k-means clusters the artwork's colors are grouped into
+	paletteIterations = 10   // k-means refinement passes
+	paletteSamples    = 4096 // Roughly how many pixels are sampled
+	paletteMinChroma  = 0.03 // Below this the artwork is effectively gray: no palette
+
+	// WCAG contrast ratios each role must reach against the background
+	paletteTextContrast   = 4.5 // Accent, secondary and muted text (AA)
+	paletteBorderContrast = 3.0 // Non-text UI (the border)
+)
+
+// paletteStrategies are the ui.palette choices
+var paletteStrategies = []string{"vibrant", "muted", "pastel", "complementary"}
+
+// palette is the set of colors View draws with, extracted from the artwork.
+// Empty roles fall back to the UI color and the default grays.
+type palette struct {
+	accent    string // Title, labels and timestamps
+	secondary string // Progress bar fill
+	muted     string // Muted text, the empty progress track and the help hint
+	border    string // Box border
+}
+
+// oklab is a color in the OKLab perceptual space
+type oklab struct {
+	L, a, b float64
+}
+
+func (c oklab) chroma() float64 {
+	return math.Hypot(c.a, c.b)
+}
+
+func (c oklab) hue() float64 {
+	return math.Atan2(c.b, c.a)
+}
+
+// withChroma returns c with its chroma (saturation) scaled to chroma
+func (c oklab) withChroma(chroma float64) oklab {
+	current := c.chroma()
+	if current == 0 {
+		return oklab{c.L, 0, 0}
+	}
+	return oklab{c.L, c.a * chroma / current, c.b * chroma / current}
+}
+
+func srgbToLinear(c float64) float64 {
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(c float64) float64 {
+	if c <= 0.0031308 {
+		return c * 12.92
+	}
+	return 1.055*math.Pow(c, 1/2.4) - 0.055
+}
+
+// rgbToOklab converts 8-bit sRGB to OKLab
+func rgbToOklab(rgb [3]uint8) oklab {
+	r := srgbToLinear(float64(rgb[0]) / 255)
+	g := srgbToLinear(float64(rgb[1]) / 255)
+	b := srgbToLinear(float64(rgb[2]) / 255)
+
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+
+	return oklab{
+		L: 0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		a: 1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		b: 0.0259040371*l + 0.7827717662*m - 0.8086757660*s,
+	}
+}
+
+// oklabToLinear converts OKLab to linear sRGB, which may be out of gamut
+func oklabToLinear(c oklab) [3]float64 {
+	l := c.L + 0.3963377774*c.a + 0.2158037573*c.b
+	m := c.L - 0.1055613458*c.a - 0.0638541728*c.b
+	s := c.L - 0.0894841775*c.a - 1.2914855480*c.b
+	l, m, s = l*l*l, m*m*m, s*s*s
+
+	return [3]float64{
+		4.0767416621*l - 3.3077115913*m + 0.2309699292*s,
+		-1.2684380046*l + 2.6097574011*m - 0.3413193965*s,
+		-0.0041960863*l - 0.7034186147*m + 1.7076147010*s,
+	}
+}
+
+func inGamut(lin [3]float64) bool {
+	for _, v := range lin {
+		if v < -1e-4 || v > 1+1e-4 {
+			return false
+		}
+	}
+	return true
+}
+
+// oklabToRGB converts OKLab to 8-bit sRGB, reducing chroma (keeping
+// lightness and hue) until the color fits in the sRGB gamut
+func oklabToRGB(c oklab) [3]uint8 {
+	c.L = clamp01(c.L)
+	lin := oklabToLinear(c)
+	if !inGamut(lin) {
+		lo, hi := 0.0, c.chroma()
+		for i := 0; i < 20; i++ {
+			mid := (lo + hi) / 2
+			if inGamut(oklabToLinear(c.withChroma(mid))) {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		lin = oklabToLinear(c.withChroma(lo))
+	}
+
+	var rgb [3]uint8
+	for i, v := range lin {
+		rgb[i] = uint8(math.Round(clamp01(linearToSRGB(clamp01(v))) * 255))
+	}
+	return rgb
+}
+
+func rgbHex(rgb [3]uint8) string {
+	return fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2])
+}
+
+// relativeLuminance is the WCAG relative luminance of an sRGB color
+func relativeLuminance(rgb [3]uint8) float64 {
+	r := srgbToLinear(float64(rgb[0]) / 255)
+	g := srgbToLinear(float64(rgb[1]) / 255)
+	b := srgbToLinear(float64(rgb[2]) / 255)
+	return 0.2126*r + 0.7152*g + 0.0722*b
+}
+
+// contrastRatio is the WCAG contrast ratio between two colors (1-21)
+func contrastRatio(x, y [3]uint8) float64 {
+	lx, ly := relativeLuminance(x), relativeLuminance(y)
+	if lx < ly {
+		lx, ly = ly, lx
+	}
+	return (lx + 0.05) / (ly + 0.05)
+}
+
+// ensureContrast moves c's lightness away from the background until it
+// reaches minRatio, toward white or black, whichever contrasts more
+func ensureContrast(c oklab, background [3]uint8, minRatio float64) [3]uint8 {
+	step := 0.02
+	if contrastRatio([3]uint8{0, 0, 0}, background) > contrastRatio([3]uint8{255, 255, 255}, background) {
+		step = -step
+	}
+	rgb := oklabToRGB(c)
+	for i := 0; i < 60 && contrastRatio(rgb, background) < minRatio; i++ {
+		c.L = clamp01(c.L + step)
+		rgb = oklabToRGB(c)
+	}
+	return rgb
+}
+
+// paletteCluster is one k-means cluster of the artwork's colors
+type paletteCluster struct {
+	center oklab
+	weight float64 // Share of the sampled pixels (0-1)
+}
+
+// clusterColors groups OKLab samples into up to k clusters. Centers start at
+// evenly spaced samples ordered by lightness, so results are deterministic.
+func clusterColors(samples []oklab, k int) []paletteCluster {
+	if len(samples) < k {
+		k = len(samples)
+	}
+	sorted := append([]oklab(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].L < sorted[j].L })
+	centers := make([]oklab, k)
+	for i := range centers {
+		centers[i] = sorted[(2*i+1)*len(sorted)/(2*k)]
+	}
+
+	assign := make([]int, len(samples))
+	counts := make([]int, k)
+	for iter := 0; iter < paletteIterations; iter++ {
+		for i, s := range samples {
+			best, bestDist := 0, math.Inf(1)
+			for j, c := range centers {
+				d := (s.L-c.L)*(s.L-c.L) + (s.a-c.a)*(s.a-c.a) + (s.b-c.b)*(s.b-c.b)
+				if d < bestDist {
+					best, bestDist = j, d
+				}
+			}
+			assign[i] = best
+		}
+
+		sums := make([]oklab, k)
+		counts = make([]int, k)
+		for i, s := range samples {
+			j := assign[i]
+			sums[j].L += s.L
+			sums[j].a += s.a
+			sums[j].b += s.b
+			counts[j]++
+		}
+		for j := range centers {
+			if counts[j] > 0 {
+				n := float64(counts[j])
+				centers[j] = oklab{sums[j].L / n, sums[j].a / n, sums[j].b / n}
+			}
+		}
+	}
+
+	clusters := make([]paletteCluster, 0, k)
+	for j, c := range centers {
+		if counts[j] > 0 {
+			clusters = append(clusters, paletteCluster{c, float64(counts[j]) / float64(len(samples))})
+		}
+	}
+	return clusters
+}
+
+// paletteScore rates a cluster as the accent for a strategy
+func paletteScore(c paletteCluster, strategy string) float64 {
+	chroma := c.center.chroma()
+	// Readable without much correction: not too dark, not white
+	lightness := 1.0
+	if c.center.L < 0.35 || c.center.L > 0.95 {
+		lightness = 0.3
+	}
+	switch strategy {
+	case "muted":
+		// Clearly tinted but subdued, and common in the artwork
+		return lightness * (0.2 + c.weight) / (1 + 30*math.Abs(chroma-0.06))
+	default:
+		// vibrant, pastel and complementary all start from the liveliest color
+		return lightness * chroma * (0.5 + c.weight)
+	}
+}
+
+// styleColor applies a strategy's look to a picked color
+func styleColor(c oklab, strategy string) oklab {
+	switch strategy {
+	case "muted":
+		c = c.withChroma(math.Min(c.chroma(), 0.07))
+	case "pastel":
+		c = c.withChroma(math.Min(c.chroma(), 0.09))
+		c.L = math.Max(c.L, 0.85)
+	}
+	return c
+}
+
+// hueDistance is the angle between two hues (radians, 0-π)
+func hueDistance(x, y float64) float64 {
+	d := math.Abs(x - y)
+	if d > math.Pi {
+		d = 2*math.Pi - d
+	}
+	return d
+}
+
+// extractPalette clusters the artwork's colors in OKLab and picks the palette
+// roles for a ui.palette strategy, each adjusted to stay readable against
+// background. Fails for (near-)grayscale artwork, like extractDominantColorOn.
+func extractPalette(img image.Image, strategy string, background [3]uint8) (palette, error) {
+	if img == nil {
+		return palette{}, fmt.Errorf("nil image")
+	}
+
+	bounds := img.Bounds()
+	step := int(math.Sqrt(float64(bounds.Dx()*bounds.Dy()) / paletteSamples))
+	if step < 1 {
+		step = 1
+	}
+	var samples []oklab
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			r, g, b, a := img.At(x, y).RGBA()
+			if a < 32768 {
+				continue // Transparent
+			}
+			// Un-premultiply, then 16-bit to 8-bit
+			samples = append(samples, rgbToOklab([3]uint8{
+				uint8(r * 0xffff / a >> 8), uint8(g * 0xffff / a >> 8), uint8(b * 0xffff / a >> 8),
+			}))
+		}
+	}
+	if len(samples) == 0 {
+		return palette{}, fmt.Errorf("no opaque pixels")
+	}
+
+	clusters := clusterColors(samples, paletteClusters)
+	sort.Slice(clusters, func(i, j int) bool {
+		return paletteScore(clusters[i], strategy) > paletteScore(clusters[j], strategy)
+	})
+	accent := clusters[0].center
+	if accent.chroma() < paletteMinChroma {
+		return palette{}, fmt.Errorf("no suitable colors found")
+	}
+
+	// Secondary: the best cluster of a clearly different hue, the opposite
+	// hue for complementary, or else a lighter shade of the accent
+	secondary := oklab{math.Min(accent.L+0.12, 1), accent.a, accent.b}
+	if strategy == "complementary" {
+		secondary = oklab{accent.L, -accent.a, -accent.b}
+	} else {
+		for _, c := range clusters[1:] {
+			if c.center.chroma() >= paletteMinChroma && hueDistance(c.center.hue(), accent.hue()) > math.Pi/6 {
+				secondary = c.center
+				break
+			}
+		}
+	}
+
+	accent = styleColor(accent, strategy)
+	secondary = styleColor(secondary, strategy)
+	// Muted: the accent's hue, barely tinted; border: a softer accent
+	muted := oklab{0.55, accent.a, accent.b}.withChroma(math.Min(accent.chroma(), 0.025))
+	border := accent.withChroma(accent.chroma() * 0.7)
+	border.L *= 0.85
+
+	return palette{
+		accent:    rgbHex(ensureContrast(accent, background, paletteTextContrast)),
+		secondary: rgbHex(ensureContrast(secondary, background, paletteTextContrast)),
+		muted:     rgbHex(ensureContrast(muted, background, paletteTextContrast)),
+		border:    rgbHex(ensureContrast(border, background, paletteBorderContrast)),
+	}, nil
+}
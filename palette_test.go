@@ -0,0 +1,129 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// twoToneImage is half one color, half another
+func twoToneImage(left, right color.RGBA) *image.RGBA {
+	img := generateTestImage(40, 40, left)
+	for y := 0; y < 40; y++ {
+		for x := 20; x < 40; x++ {
+			img.SetRGBA(x, y, right)
+		}
+	}
+	return img
+}
+
+// TestOklabRoundTrip verifies sRGB survives a trip through OKLab
+func TestOklabRoundTrip(t *testing.T) {
+	for _, rgb := range [][3]uint8{{0, 0, 0}, {255, 255, 255}, {200, 40, 40}, {30, 144, 255}, {128, 128, 0}} {
+		if got := oklabToRGB(rgbToOklab(rgb)); got != rgb {
+			t.Errorf("%v round-tripped to %v", rgb, got)
+		}
+	}
+
+	white := rgbToOklab([3]uint8{255, 255, 255})
+	if math.Abs(white.L-1) > 1e-3 || white.chroma() > 1e-3 {
+		t.Errorf("white = %+v, want L=1 and no chroma", white)
+	}
+
+	// Out-of-gamut colors keep their lightness, losing chroma instead
+	vivid := oklab{0.7, 0.4, 0}
+	if got := rgbToOklab(oklabToRGB(vivid)); math.Abs(got.L-0.7) > 0.01 {
+		t.Errorf("gamut mapping changed lightness to %.3f", got.L)
+	}
+}
+
+// TestContrastRatio verifies the WCAG contrast formula
+func TestContrastRatio(t *testing.T) {
+	black, white := [3]uint8{0, 0, 0}, [3]uint8{255, 255, 255}
+	if got := contrastRatio(black, white); math.Abs(got-21) > 0.01 {
+		t.Errorf("black/white = %.2f, want 21", got)
+	}
+	if got := contrastRatio(white, white); got != 1 {
+		t.Errorf("white/white = %.2f, want 1", got)
+	}
+
+	// A dark blue on a dark background is lightened until readable
+	dark := [3]uint8{0x1e, 0x1e, 0x1e}
+	fixed := ensureContrast(rgbToOklab([3]uint8{20, 20, 90}), dark, paletteTextContrast)
+	if contrastRatio(fixed, dark) < paletteTextContrast {
+		t.Errorf("%v still unreadable on %v", fixed, dark)
+	}
+	// ...and a light yellow on white is darkened
+	fixed = ensureContrast(rgbToOklab([3]uint8{250, 240, 150}), white, paletteTextContrast)
+	if contrastRatio(fixed, white) < paletteTextContrast {
+		t.Errorf("%v still unreadable on white", fixed)
+	}
+}
+
+// TestExtractPalette verifies every role is readable on the background and
+// the strategies pick differently
+func TestExtractPalette(t *testing.T) {
+	img := twoToneImage(color.RGBA{220, 30, 30, 255}, color.RGBA{40, 90, 220, 255})
+
+	for _, background := range [][3]uint8{darkBackground, {255, 255, 255}} {
+		for _, strategy := range paletteStrategies {
+			p, err := extractPalette(img, strategy, background)
+			assertNoError(t, err)
+			roles := map[string]float64{
+				p.accent:    paletteTextContrast,
+				p.secondary: paletteTextContrast,
+				p.muted:     paletteTextContrast,
+				p.border:    paletteBorderContrast,
+			}
+			for hex, want := range roles {
+				rgb, ok := parseHexColor(hex)
+				if !ok {
+					t.Fatalf("%s: invalid color %q", strategy, hex)
+				}
+				if got := contrastRatio(rgb, background); got < want-0.01 {
+					t.Errorf("%s on %v: %s has contrast %.2f, want >= %.1f", strategy, background, hex, got, want)
+				}
+			}
+		}
+	}
+
+	vibrant, _ := extractPalette(img, "vibrant", darkBackground)
+	accent, _ := parseHexColor(vibrant.accent)
+	secondary, _ := parseHexColor(vibrant.secondary)
+	if hueDistance(rgbToOklab(accent).hue(), rgbToOklab(secondary).hue()) < math.Pi/6 {
+		t.Errorf("secondary %s should differ in hue from accent %s", vibrant.secondary, vibrant.accent)
+	}
+
+	pastel, _ := extractPalette(img, "pastel", darkBackground)
+	pastelAccent, _ := parseHexColor(pastel.accent)
+	if rgbToOklab(pastelAccent).L < 0.84 {
+		t.Errorf("pastel accent %s should be light", pastel.accent)
+	}
+
+	muted, _ := extractPalette(img, "muted", darkBackground)
+	mutedAccent, _ := parseHexColor(muted.accent)
+	if rgbToOklab(mutedAccent).chroma() > rgbToOklab(accent).chroma() {
+		t.Errorf("muted accent %s should be less saturated than vibrant %s", muted.accent, vibrant.accent)
+	}
+
+	complementary, _ := extractPalette(img, "complementary", darkBackground)
+	compAccent, _ := parseHexColor(complementary.accent)
+	compSecondary, _ := parseHexColor(complementary.secondary)
+	if hueDistance(rgbToOklab(compAccent).hue(), rgbToOklab(compSecondary).hue()) < math.Pi*0.8 {
+		t.Errorf("complementary secondary %s should be opposite %s", complementary.secondary, complementary.accent)
+	}
+}
+
+// TestExtractPaletteUnsuitable verifies gray or empty artwork has no palette
+func TestExtractPaletteUnsuitable(t *testing.T) {
+	gray := generateGradientImage(40, 40, color.RGBA{0, 0, 0, 255}, color.RGBA{255, 255, 255, 255})
+	_, err := extractPalette(gray, "vibrant", darkBackground)
+	assertError(t, err, "grayscale artwork")
+
+	_, err = extractPalette(image.NewRGBA(image.Rect(0, 0, 8, 8)), "vibrant", darkBackground)
+	assertError(t, err, "transparent artwork")
+
+	_, err = extractPalette(nil, "vibrant", darkBackground)
+	assertError(t, err, "nil image")
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Progress bar styles (ui.progress_style)
+const (
+	progressBlock    = "block"    // Whole cells: the classic look
+	progressSmooth   = "smooth"   // Eighth blocks: fills a cell an eighth at a time
+	progressBraille  = "braille"  // Braille dots, half a cell at a time
+	progressThin     = "thin"     // A heavy line over a light one
+	progressDotted   = "dotted"   // Dots
+	progressGradient = "gradient" // Smooth blocks shading from the accent to the secondary color
+)
+
+var progressStyles = []string{progressBlock, progressSmooth, progressBraille, progressThin, progressDotted, progressGradient}
+
+// Time display modes beside the bar (ui.time_display; t cycles them)
+const (
+	timeTotal     = "total"     // 01:05/03:20
+	timeElapsed   = "elapsed"   // 01:05
+	timeRemaining = "remaining" // -02:15
+)
+
+var timeDisplays = []string{timeTotal, timeElapsed, timeRemaining}
+
+// eighths are the left-aligned blocks one to seven eighths wide
+var eighths = []string{"▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+// barGlyphs draws one progress style
+type barGlyphs struct {
+	fill, track string
+	partial     []string // A partly filled cell, emptiest first (sub-cell precision)
+	gradient    bool     // Fill shades from the title to the progress_fill color
+}
+
+var barGlyphSets = map[string]barGlyphs{
+	progressBlock:    {fill: "█", track: "─"},
+	progressSmooth:   {fill: "█", track: "─", partial: eighths},
+	progressBraille:  {fill: "⣿", track: "⣀", partial: []string{"⡇"}},
+	progressThin:     {fill: "━", track: "─", partial: []string{"╸"}},
+	progressDotted:   {fill: "•", track: "·"},
+	progressGradient: {fill: "█", track: "─", partial: eighths, gradient: true},
+}
+
+// renderBar draws a progress bar width cells wide
+func renderBar(cfg Config, styles themeStyles, width int, progress float64) string {
+	glyphs, ok := barGlyphSets[cfg.UI.ProgressStyle]
+	if !ok {
+		glyphs = barGlyphSets[progressBlock]
+	}
+	progress = clamp01(progress)
+
+	// Whole cells, then the partly filled one in steps of the style's precision
+	cells := progress * float64(width)
+	full := min(int(cells), width)
+	steps := len(glyphs.partial) + 1
+	part := int((cells - float64(full)) * float64(steps))
+
+	var bar strings.Builder
+	if glyphs.gradient {
+		colors := gradientColors(cfg, styles, width)
+		for i := 0; i < full; i++ {
+			bar.WriteString(styles.fill.Foreground(colors[i]).Render(glyphs.fill))
+		}
+		if part > 0 && full < width {
+			bar.WriteString(styles.fill.Foreground(colors[full]).Render(glyphs.partial[part-1]))
+			full++
+		}
+	} else {
+		fill := strings.Repeat(glyphs.fill, full)
+		if part > 0 && full < width {
+			fill += glyphs.partial[part-1]
+			full++
+		}
+		bar.WriteString(styles.fill.Render(fill))
+	}
+	bar.WriteString(styles.track.Render(strings.Repeat(glyphs.track, width-full)))
+	return bar.String()
+}
+
+// gradientColors shades n cells from the title (accent) color to the
+// progress_fill (secondary) color in OKLab. Without two colors to blend,
+// every cell keeps the fill color.
+func gradientColors(cfg Config, styles themeStyles, n int) []lipgloss.TerminalColor {
+	colors := make([]lipgloss.TerminalColor, n)
+	for i := range colors {
+		colors[i] = styles.fill.GetForeground()
+	}
+	from, okFrom := terminalColorRGB(styles.title.GetForeground())
+	to, okTo := terminalColorRGB(styles.fill.GetForeground())
+	if !okFrom || !okTo || n < 2 {
+		return colors
+	}
+	a, b := rgbToOklab(from), rgbToOklab(to)
+	profile := colorProfileFor(cfg)
+	for i := range colors {
+		t := float64(i) / float64(n-1)
+		mixed := oklab{a.L + (b.L-a.L)*t, a.a + (b.a-a.a)*t, a.b + (b.b-a.b)*t}
+		colors[i] = lipgloss.Color(downsampleColor(rgbHex(oklabToRGB(mixed)), profile))
+	}
+	return colors
+}
+
+// terminalColorRGB is a style color's RGB value, for hex colors and ANSI
+// indexes (not the terminal's default foreground)
+func terminalColorRGB(c lipgloss.TerminalColor) ([3]uint8, bool) {
+	color, ok := c.(lipgloss.Color)
+	if !ok || color == "" {
+		return [3]uint8{}, false
+	}
+	if rgb, ok := parseHexColor(string(color)); ok {
+		return rgb, true
+	}
+	if i, err := strconv.Atoi(string(color)); err == nil && i >= 0 && i <= 255 {
+		return ansi256RGB(i), true
+	}
+	return [3]uint8{}, false
+}
+
+// timeText is the time beside the progress bar in the ui.time_display mode
+func (m model) timeText(cfg Config) string {
+	position := min(int64(m.getCurrentPosition()), m.duration)
+	switch cfg.UI.TimeDisplay {
+	case timeElapsed:
+		return formatTime(position)
+	case timeRemaining:
+		return "-" + formatTime(m.duration-position)
+	}
+	return formatTime(position) + "/" + formatTime(m.duration)
+}
+
+// nextTimeDisplay is the time display t switches to
+func nextTimeDisplay(current string) string {
+	i := slices.Index(timeDisplays, current)
+	return timeDisplays[(i+1)%len(timeDisplays)]
+}
+
+// progressBarWidth is the bar's width with time beside it: the layout's,
+// less what a time longer than progressTimeWidth (hours, a minus sign)
+// takes
+func progressBarWidth(barWidth int, time string) int {
+	return max(barWidth-max(cellWidth(time)+1-progressTimeWidth, 0), 0)
+}
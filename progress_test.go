@@ -0,0 +1,75 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TestRenderBar verifies every style fills its width, with sub-cell steps
+// where the style has them
+func TestRenderBar(t *testing.T) {
+	cfg := layoutTestConfig(layoutCard)
+	styles := model{}.styles(theme{}, cfg)
+
+	for _, style := range progressStyles {
+		cfg.UI.ProgressStyle = style
+		for _, progress := range []float64{0, 0.07, 0.5, 0.93, 1, 1.5} {
+			if got := lipgloss.Width(renderBar(cfg, styles, 20, progress)); got != 20 {
+				t.Errorf("%s at %.2f: width %d, want 20", style, progress, got)
+			}
+		}
+	}
+
+	// 10 cells at 0.55 is five and a half: a half block in smooth, none in block
+	cfg.UI.ProgressStyle = progressSmooth
+	assertEqual(t, renderBar(cfg, styles, 10, 0.55), "█████▌────", "smooth")
+	cfg.UI.ProgressStyle = progressBlock
+	assertEqual(t, renderBar(cfg, styles, 10, 0.55), "█████─────", "block")
+	cfg.UI.ProgressStyle = progressBraille
+	assertEqual(t, renderBar(cfg, styles, 10, 0.55), "⣿⣿⣿⣿⣿⡇⣀⣀⣀⣀", "braille")
+}
+
+// TestGradientColors verifies the gradient runs from the accent to the fill
+func TestGradientColors(t *testing.T) {
+	styles := themeStyles{
+		title: lipgloss.NewStyle().Foreground(lipgloss.Color("#ff0000")),
+		fill:  lipgloss.NewStyle().Foreground(lipgloss.Color("#0000ff")),
+	}
+	cfg := layoutTestConfig(layoutCard)
+	cfg.UI.ColorProfile = colorProfileTrueColor
+
+	colors := gradientColors(cfg, styles, 5)
+	assertEqual(t, colors[0], lipgloss.TerminalColor(lipgloss.Color("#ff0000")), "first")
+	assertEqual(t, colors[4], lipgloss.TerminalColor(lipgloss.Color("#0000ff")), "last")
+	if colors[2] == colors[0] || colors[2] == colors[4] {
+		t.Errorf("middle color %v not blended", colors[2])
+	}
+
+	// No accent to blend from: solid fill
+	styles.title = lipgloss.NewStyle()
+	assertEqual(t, gradientColors(cfg, styles, 3)[1], styles.fill.GetForeground(), "solid fallback")
+}
+
+// TestTimeText verifies each time display and the bar giving way to it
+func TestTimeText(t *testing.T) {
+	cfg := layoutTestConfig(layoutCard)
+	m := model{duration: 200, lastPosition: 65}
+
+	for display, want := range map[string]string{timeTotal: "01:05/03:20", timeElapsed: "01:05", timeRemaining: "-02:15"} {
+		cfg.UI.TimeDisplay = display
+		assertEqual(t, m.timeText(cfg), want, display)
+	}
+	assertEqual(t, nextTimeDisplay(timeRemaining), timeTotal, "cycle wraps")
+
+	m = model{duration: 4500, lastPosition: 65}
+	cfg.UI.TimeDisplay = timeTotal
+	text := m.timeText(cfg)
+	assertEqual(t, text, "01:05/1:15:00", "hours")
+	assertEqual(t, progressBarWidth(30, "01:05/03:20"), 30, "fits")
+	assertEqual(t, progressBarWidth(30, text), 29, "hours take a cell")
+	if !strings.HasPrefix(m.timeText(Config{}), "01:05") {
+		t.Error("unset display isn't total")
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Overflow modes for text wider than the room it has (text.overflow)
+const (
+	overflowMarquee  = "marquee"  // Loop around, with a separator
+	overflowBounce   = "bounce"   // Scroll to the end, then back
+	overflowEllipsis = "ellipsis" // Cut off with "…"
+	overflowWrap     = "wrap"     // Continue on the rows below
+)
+
+var overflowModes = []string{overflowMarquee, overflowBounce, overflowEllipsis, overflowWrap}
+
+// scrollState is one line's scrolling. Each line has its own, so a short
+// title doesn't move in lockstep with a long album.
+type scrollState struct {
+	offset int       // Graphemes scrolled
+	back   bool      // Bounce: heading back to the start
+	next   time.Time // When the next step is due (zero = not scrolling)
+}
+
+// scrollSpan is how many steps a pass takes: the text and separator for
+// marquee, the part that doesn't fit for bounce, none if it fits or the
+// mode doesn't scroll
+func scrollSpan(text string, width int, mode string) int {
+	if cellWidth(text) <= width {
+		return 0
+	}
+	switch mode {
+	case overflowMarquee:
+		return len(graphemes(text)) + scrollSeparatorLen
+	case overflowBounce:
+		// Steps until the rest of the text fits
+		rest := cellWidth(text)
+		for i, cluster := range graphemes(text) {
+			if rest <= width {
+				return i
+			}
+			rest -= cellWidth(cluster)
+		}
+	}
+	return 0
+}
+
+// advance moves the scroll on to now at text.scroll_speed, pausing for
+// text.scroll_pause_ms before each pass. Steps follow the clock rather
+// than the tick, so the speed is the same at every tick rate.
+func (s *scrollState) advance(cfg Config, now time.Time, span int, mode string) {
+	if span == 0 {
+		*s = scrollState{}
+		return
+	}
+	pause := time.Duration(cfg.Text.ScrollPauseMs) * time.Millisecond
+	step := time.Second / time.Duration(max(cfg.Text.ScrollSpeed, 1))
+
+	s.offset = min(s.offset, span)
+	if s.next.IsZero() {
+		// Just started overflowing: let it be read first
+		s.next = now.Add(pause)
+	}
+	if now.Sub(s.next) > time.Second {
+		// Don't race to catch up after a stall (e.g. a suspended laptop)
+		s.next = now
+	}
+
+	for !now.Before(s.next) {
+		s.next = s.next.Add(step)
+		switch mode {
+		case overflowMarquee:
+			s.offset++
+			if s.offset >= span {
+				// Back where it started: pause before going round again
+				s.offset = 0
+				s.next = now.Add(pause)
+			}
+		case overflowBounce:
+			if s.back {
+				s.offset--
+			} else {
+				s.offset++
+			}
+			if s.offset <= 0 || s.offset >= span {
+				s.back = s.offset > 0
+				s.next = now.Add(pause)
+			}
+		}
+	}
+}
+
+// fitText fits text into width cells the overflow mode's way, scrolled by
+// offset. Wrapped text comes back as several lines.
+func fitText(text string, width int, mode string, offset int) string {
+	if cellWidth(text) <= width {
+		return text
+	}
+	switch mode {
+	case overflowBounce:
+		return windowText(text, width, offset)
+	case overflowEllipsis:
+		return truncateText(text, width)
+	case overflowWrap:
+		wrapped := lipgloss.NewStyle().Width(width).Render(text)
+		lines := strings.Split(wrapped, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight(line, " ")
+		}
+		return strings.Join(lines, "\n")
+	}
+	return scrollText(text, width, offset)
+}
+
+// overflow is text.overflow for the layout: mini has one row, so it
+// can't wrap
+func (m model) overflow(cfg Config, lay layout) string {
+	if lay.name == layoutMini && cfg.Text.Overflow == overflowWrap {
+		return overflowEllipsis
+	}
+	return cfg.Text.Overflow
+}
+
+// updateScrolls advances every line's scroll to now. Lines are matched by
+// position; a different number of lines starts them all over.
+func (m *model) updateScrolls(cfg Config, now time.Time, texts []string, width int, mode string) {
+	scrolls := make([]scrollState, len(texts))
+	if len(m.scrolls) == len(texts) {
+		copy(scrolls, m.scrolls)
+	}
+	for i, text := range texts {
+		scrolls[i].advance(cfg, now, scrollSpan(text, width, mode), mode)
+	}
+	m.scrolls = scrolls
+}
+
+// scrollOffset is line i's current scroll offset
+func (m model) scrollOffset(i int) int {
+	if i < len(m.scrolls) {
+		return m.scrolls[i].offset
+	}
+	return 0
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestScrollSpan verifies how far each overflow mode scrolls
+func TestScrollSpan(t *testing.T) {
+	assertEqual(t, scrollSpan("Short", 10, overflowMarquee), 0, "fits")
+	assertEqual(t, scrollSpan("0123456789ab", 10, overflowMarquee), 12+scrollSeparatorLen, "marquee")
+	assertEqual(t, scrollSpan("0123456789ab", 10, overflowBounce), 2, "bounce")
+	assertEqual(t, scrollSpan("世界世界世界", 10, overflowBounce), 1, "bounce wide")
+	assertEqual(t, scrollSpan("0123456789ab", 10, overflowEllipsis), 0, "ellipsis")
+	assertEqual(t, scrollSpan("0123456789ab", 10, overflowWrap), 0, "wrap")
+}
+
+// TestScrollSpeed verifies scrolling follows the clock, not the tick rate
+func TestScrollSpeed(t *testing.T) {
+	cfg := layoutTestConfig(layoutCard)
+	cfg.Text.ScrollSpeed = 4
+	cfg.Text.ScrollPauseMs = 1000
+	start := time.Now()
+
+	for _, tick := range []time.Duration{100 * time.Millisecond, 500 * time.Millisecond, time.Second} {
+		var s scrollState
+		for now := start; !now.After(start.Add(3 * time.Second)); now = now.Add(tick) {
+			s.advance(cfg, now, 100, overflowMarquee)
+		}
+		// One second's pause, then two seconds at 4 steps a second
+		if s.offset < 8 || s.offset > 9 {
+			t.Errorf("ticking every %v: offset %d, want 8", tick, s.offset)
+		}
+	}
+}
+
+// TestScrollBounce verifies bounce pauses at each end and comes back
+func TestScrollBounce(t *testing.T) {
+	cfg := layoutTestConfig(layoutCard)
+	cfg.Text.ScrollSpeed = 10
+	cfg.Text.ScrollPauseMs = 500
+	start := time.Now()
+
+	var s scrollState
+	offsets := map[int]bool{}
+	for now := start; now.Before(start.Add(5 * time.Second)); now = now.Add(50 * time.Millisecond) {
+		s.advance(cfg, now, 3, overflowBounce)
+		if s.offset < 0 || s.offset > 3 {
+			t.Fatalf("offset %d outside 0..3", s.offset)
+		}
+		offsets[s.offset] = true
+		if s.offset == 3 && !s.back {
+			t.Error("at the end but not heading back")
+		}
+	}
+	assertEqual(t, len(offsets), 4, "offsets visited")
+}
+
+// TestFitText verifies each overflow mode keeps text within its width
+func TestFitText(t *testing.T) {
+	text := "The quick brown fox jumps"
+	assertEqual(t, fitText("Short", 10, overflowEllipsis, 0), "Short", "fits")
+	assertEqual(t, fitText(text, 10, overflowMarquee, 4), "quick brow", "marquee")
+	assertEqual(t, fitText(text, 10, overflowBounce, 20), "jumps     ", "bounce end")
+	assertEqual(t, fitText(text, 10, overflowEllipsis, 4), "The quick…", "ellipsis")
+
+	wrapped := strings.Split(fitText(text, 10, overflowWrap, 0), "\n")
+	assertEqual(t, len(wrapped), 3, "wrapped rows")
+	for _, row := range wrapped {
+		if cellWidth(row) > 10 {
+			t.Errorf("wrapped row %q wider than 10", row)
+		}
+	}
+}
+
+// TestScrollPerLine verifies each line scrolls on its own
+func TestScrollPerLine(t *testing.T) {
+	defer config.Set(config.Get())
+	cfg := layoutTestConfig(layoutCard)
+	config.Set(cfg)
+
+	m := model{width: 80, height: 30}
+	m.songData = SongData{
+		Title:  "Short",
+		Artist: strings.Repeat("Long artist ", 4),
+		Album:  strings.Repeat("Even longer album name ", 3),
+	}
+	start := time.Now()
+	updated, _ := m.Update(tickMsg(start))
+	updated, _ = updated.(model).Update(tickMsg(start.Add(4 * time.Second)))
+	m = updated.(model)
+
+	assertEqual(t, m.scrollOffset(0), 0, "title fits")
+	if m.scrollOffset(1) == 0 || m.scrollOffset(1) != m.scrollOffset(2) {
+		t.Errorf("artist and album offsets %d, %d: want both scrolling", m.scrollOffset(1), m.scrollOffset(2))
+	}
+
+	// The artist loops back (and pauses) long before the album does
+	for i := 0; i < 200 && m.scrollOffset(1) != 0; i++ {
+		start = start.Add(100 * time.Millisecond)
+		updated, _ = m.Update(tickMsg(start.Add(4 * time.Second)))
+		m = updated.(model)
+	}
+	assertEqual(t, m.scrollOffset(1), 0, "artist looped")
+	if m.scrollOffset(2) == 0 {
+		t.Error("album looped in lockstep with the artist")
+	}
+}
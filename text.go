@@ -2,31 +2,97 @@ package main
 
 import (
 	"fmt"
+	"strings"
+
+	"github.com/rivo/uniseg"
 )
 
-// formatTime converts seconds to MM:SS format
+// formatTime converts seconds to MM:SS format, or H:MM:SS from an hour on
 func formatTime(seconds int64) string {
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
 	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
 }
 
-// scrollText returns a scrolling window of text with smooth looping
+// cellWidth is how many terminal cells text takes: wide (CJK, emoji)
+// characters count two, combining marks and joiners none
+func cellWidth(text string) int {
+	return uniseg.StringWidth(text)
+}
+
+// graphemes splits text into grapheme clusters, the characters a user
+// sees, so a flag or a ZWJ emoji sequence is never cut in half
+func graphemes(text string) []string {
+	var clusters []string
+	g := uniseg.NewGraphemes(text)
+	for g.Next() {
+		clusters = append(clusters, g.Str())
+	}
+	return clusters
+}
+
+// scrollText returns a scrolling window of text with smooth looping. max is
+// in cells and offset in graphemes; a wide character that would straddle
+// the edge is left out and the window padded, so it never overflows.
 func scrollText(text string, max int, offset int) string {
-	runes := []rune(text)
-	if len(runes) <= max {
+	if cellWidth(text) <= max {
 		return text
 	}
 
 	// Add padding for smooth loop
-	fullText := append(runes, []rune(scrollSeparator)...)
+	fullText := append(graphemes(text), graphemes(scrollSeparator)...)
 	textLen := len(fullText)
 
 	// Wrap offset around
 	offset = offset % textLen
 
 	// Build visible window
-	var result []rune
-	for i := 0; i < max; i++ {
-		result = append(result, fullText[(offset+i)%textLen])
+	var result strings.Builder
+	width := 0
+	for i := 0; i < textLen; i++ {
+		cluster := fullText[(offset+i)%textLen]
+		w := cellWidth(cluster)
+		if width+w > max {
+			break
+		}
+		result.WriteString(cluster)
+		width += w
+	}
+	return result.String() + strings.Repeat(" ", max-width)
+}
+
+// windowText is text from grapheme offset on, cut to width cells and
+// padded to fill them (bounce's window; it doesn't wrap around)
+func windowText(text string, width int, offset int) string {
+	clusters := graphemes(text)
+	var result strings.Builder
+	used := 0
+	for _, cluster := range clusters[min(offset, len(clusters)):] {
+		w := cellWidth(cluster)
+		if used+w > width {
+			break
+		}
+		result.WriteString(cluster)
+		used += w
+	}
+	return result.String() + strings.Repeat(" ", width-used)
+}
+
+// truncateText shortens text to max cells, ending in "…"
+func truncateText(text string, max int) string {
+	if max < 1 || cellWidth(text) <= max {
+		return text
+	}
+	var result strings.Builder
+	width := 0
+	for _, cluster := range graphemes(text) {
+		w := cellWidth(cluster)
+		if width+w > max-1 {
+			break
+		}
+		result.WriteString(cluster)
+		width += w
 	}
-	return string(result)
+	return result.String() + "…"
 }
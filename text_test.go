@@ -1,6 +1,7 @@
 package main
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -19,9 +20,9 @@ func TestFormatTime(t *testing.T) {
 		{"over one minute", 75, "01:15"},
 		{"exactly 10 minutes", 600, "10:00"},
 		{"under one hour", 3599, "59:59"},
-		{"exactly one hour", 3600, "60:00"},
-		{"over one hour", 3661, "61:01"},
-		{"multiple hours", 7384, "123:04"},
+		{"exactly one hour", 3600, "1:00:00"},
+		{"over one hour", 3661, "1:01:01"},
+		{"multiple hours", 7384, "2:03:04"},
 	}
 
 	for _, tt := range tests {
@@ -95,14 +96,42 @@ func TestScrollText(t *testing.T) {
 			text:      "Hello 世界 🎵 Music",
 			maxLength: 10,
 			offset:    0,
-			expected:  "Hello 世界 🎵",
+			expected:  "Hello 世界",
 		},
 		{
 			name:      "unicode with scroll",
 			text:      "Hello 世界 🎵 Music Player",
 			maxLength: 10,
 			offset:    6,
-			expected:  "世界 🎵 Music",
+			expected:  "世界 🎵 Mu",
+		},
+		{
+			name:      "wide character at the edge",
+			text:      "ab世界cd",
+			maxLength: 5,
+			offset:    1,
+			expected:  "b世界",
+		},
+		{
+			name:      "wide character past the edge padded",
+			text:      "ab世界cd",
+			maxLength: 5,
+			offset:    0,
+			expected:  "ab世 ",
+		},
+		{
+			name:      "ZWJ sequence kept whole",
+			text:      "👩‍🎤 Artist Name",
+			maxLength: 8,
+			offset:    0,
+			expected:  "👩‍🎤 Artis",
+		},
+		{
+			name:      "combining marks take no room",
+			text:      "Cafe\u0301 del Mar",
+			maxLength: 5,
+			offset:    0,
+			expected:  "Cafe\u0301 ",
 		},
 		{
 			name:      "empty text",
@@ -219,3 +248,27 @@ func BenchmarkScrollTextUnicode(b *testing.B) {
 		scrollText(text, maxLength, offset)
 	}
 }
+
+// TestScrollTextWidth verifies every window of wide text fills the width
+// exactly and never splits a grapheme
+func TestScrollTextWidth(t *testing.T) {
+	text := "日本語 🇯🇵 👨‍👩‍👧 Tokyo"
+	clusters := graphemes(text)
+	for offset := 0; offset < len(clusters)+scrollSeparatorLen; offset++ {
+		result := scrollText(text, 7, offset)
+		assertEqual(t, cellWidth(result), 7, "window width")
+		for _, cluster := range graphemes(result) {
+			if cluster != " " && !strings.Contains(text+scrollSeparator, cluster) {
+				t.Errorf("offset %d: split grapheme %q", offset, cluster)
+			}
+		}
+	}
+}
+
+// TestTruncateText verifies truncation counts cells
+func TestTruncateText(t *testing.T) {
+	assertEqual(t, truncateText("Short", 10), "Short", "fits")
+	assertEqual(t, truncateText("Long title", 5), "Long…", "ascii")
+	assertEqual(t, truncateText("世界世界", 5), "世界…", "wide")
+	assertEqual(t, truncateText("👩‍🎤👩‍🎤👩‍🎤", 4), "👩‍🎤…", "ZWJ")
+}
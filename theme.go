@@ -0,0 +1,254 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fsnotify/fsnotify"
+	"go.yaml.in/yaml/v3"
+)
+
+const defaultThemeName = "default"
+
+// Built-in theme presets, one YAML file per theme
+//
+//go:embed themes/*.yaml
+var builtinThemes embed.FS
+
+// themeRoles are the styles a theme defines, in the order View uses them
+var themeRoles = []string{"title", "label", "border", "muted", "dim", "error", "progress_fill", "progress_empty"}
+
+// themeBorders are the box border types a theme can pick
+var themeBorders = map[string]lipgloss.Border{
+	"rounded": lipgloss.RoundedBorder(),
+	"normal":  lipgloss.NormalBorder(),
+	"thick":   lipgloss.ThickBorder(),
+	"double":  lipgloss.DoubleBorder(),
+	"hidden":  lipgloss.HiddenBorder(),
+}
+
+// themeStyle is one role's style as written in a theme file
+type themeStyle struct {
+	Fg     string `yaml:"fg"`
+	Bg     string `yaml:"bg"`
+	Bold   bool   `yaml:"bold"`
+	Border string `yaml:"border"` // Box border type (border role only)
+}
+
+// theme is a parsed theme file
+type theme struct {
+	// Roles the colors extracted from the artwork override (auto color
+	// mode). Listed text roles without an fg use ui.color otherwise.
+	Accent []string              `yaml:"accent"`
+	Styles map[string]themeStyle `yaml:"styles"`
+}
+
+// SafeTheme holds the active theme with thread-safe access, like SafeConfig
+type SafeTheme struct {
+	mu sync.RWMutex
+	t  theme
+}
+
+func (st *SafeTheme) Get() theme {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	return st.t
+}
+
+func (st *SafeTheme) Set(t theme) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.t = t
+}
+
+var currentTheme = &SafeTheme{}
+
+// themesDir is where user themes live (~/.config/goplaying/themes)
+func themesDir() string {
+	dir := configDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "themes")
+}
+
+// themeNames lists the built-in presets
+func themeNames() []string {
+	entries, _ := builtinThemes.ReadDir("themes")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".yaml"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// loadTheme reads a theme by name: a file in the themes directory, which
+// takes precedence, or a built-in preset
+func loadTheme(name string) (theme, error) {
+	if name == "" {
+		name = defaultThemeName
+	}
+	if strings.ContainsAny(name, `/\`) {
+		return theme{}, fmt.Errorf("theme name '%s' must not contain a path", name)
+	}
+
+	var data []byte
+	err := os.ErrNotExist
+	if dir := themesDir(); dir != "" {
+		data, err = os.ReadFile(filepath.Join(dir, name+".yaml"))
+	}
+	if err != nil {
+		data, err = builtinThemes.ReadFile("themes/" + name + ".yaml")
+		if err != nil {
+			return theme{}, fmt.Errorf("unknown theme '%s' (built-in: %s, or add %s.yaml to %s)",
+				name, strings.Join(themeNames(), ", "), name, themesDir())
+		}
+	}
+	return parseTheme(data)
+}
+
+// parseTheme parses and validates a theme file
+func parseTheme(data []byte) (theme, error) {
+	var t theme
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return theme{}, fmt.Errorf("invalid theme file: %w", err)
+	}
+	for _, role := range t.Accent {
+		if !slices.Contains(themeRoles, role) {
+			return theme{}, fmt.Errorf("accent: unknown role '%s'", role)
+		}
+	}
+	for role, style := range t.Styles {
+		if !slices.Contains(themeRoles, role) {
+			return theme{}, fmt.Errorf("styles: unknown role '%s' (must be %s)", role, strings.Join(themeRoles, ", "))
+		}
+		if style.Fg != "" && !isValidColor(style.Fg) {
+			return theme{}, fmt.Errorf("styles.%s.fg: invalid color '%s'", role, style.Fg)
+		}
+		if style.Bg != "" && !isValidColor(style.Bg) {
+			return theme{}, fmt.Errorf("styles.%s.bg: invalid color '%s'", role, style.Bg)
+		}
+		if _, ok := themeBorders[style.Border]; style.Border != "" && (!ok || role != "border") {
+			return theme{}, fmt.Errorf("styles.%s.border: must be set on border, to one of rounded, normal, thick, double or hidden", role)
+		}
+	}
+	return t, nil
+}
+
+// themeStyles are the resolved styles View renders with
+type themeStyles struct {
+	title, label, border, muted, dim, error, fill, track lipgloss.Style
+}
+
+// styles resolves the theme against the model's colors. A role takes, in
+// order: the extracted palette (when it's an accent role), its own fg, the
+// background's fixed color (muted, dim, progress_empty and error), the
+// accent color (other accent roles), or else the terminal's foreground.
+func (m model) styles(t theme, cfg Config) themeStyles {
+	fixed := uiColorsFor(cfg)
+	profile := colorProfileFor(cfg)
+	extracted := map[string]string{
+		"title": m.color, "label": m.color, "error": m.color,
+		"border":         m.palette.border,
+		"muted":          m.palette.muted,
+		"dim":            m.palette.muted,
+		"progress_fill":  m.palette.secondary,
+		"progress_empty": m.palette.muted,
+	}
+	fallback := map[string]string{
+		"muted": fixed.muted, "dim": fixed.dim, "error": fixed.error, "progress_empty": fixed.track,
+	}
+
+	style := func(role string) lipgloss.Style {
+		s := t.Styles[role]
+		accent := slices.Contains(t.Accent, role)
+		fg := s.Fg
+		switch {
+		case accent && m.palette.accent != "" && extracted[role] != "":
+			fg = extracted[role]
+		case fg != "":
+		case fallback[role] != "":
+			fg = fallback[role]
+		case accent:
+			fg = m.color
+		}
+
+		// Map to what the terminal can show (ui.color_profile)
+		fg, bg := downsampleColor(fg, profile), downsampleColor(s.Bg, profile)
+
+		st := lipgloss.NewStyle().Bold(s.Bold)
+		if fg != "" {
+			st = st.Foreground(lipgloss.Color(fg))
+		}
+		if bg != "" {
+			st = st.Background(lipgloss.Color(bg))
+		}
+		return st
+	}
+
+	styles := themeStyles{
+		title: style("title"), label: style("label"), muted: style("muted"), dim: style("dim"),
+		error: style("error"), fill: style("progress_fill"), track: style("progress_empty"),
+	}
+	if profile == colorProfileMono {
+		// No colors to tell roles apart: emphasis comes from attributes
+		styles.title = styles.title.Bold(true).Underline(true)
+		styles.label = styles.label.Bold(true)
+		styles.error = styles.error.Bold(true).Reverse(true)
+	}
+
+	// The border role colors the box's border rather than text
+	border := style("border")
+	kind, ok := themeBorders[t.Styles["border"].Border]
+	if !ok {
+		kind = lipgloss.RoundedBorder()
+	}
+	styles.border = lipgloss.NewStyle().
+		Border(kind).
+		BorderForeground(border.GetForeground()).
+		BorderBackground(border.GetBackground()).
+		Padding(1, 2)
+	return styles
+}
+
+// watchThemes reloads the active theme when its file in the themes
+// directory changes, waking the UI through watchConfigCmd
+func watchThemes() {
+	dir := themesDir()
+	if _, err := os.Stat(dir); err != nil {
+		return
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return
+	}
+	if err := watcher.Add(dir); err != nil {
+		_ = watcher.Close()
+		return
+	}
+
+	go func() {
+		for event := range watcher.Events {
+			name := config.Get().UI.Theme
+			if filepath.Base(event.Name) != name+".yaml" {
+				continue
+			}
+			// Editors save in several steps; a half-written file keeps the
+			// current theme until the next event
+			t, err := loadTheme(name)
+			if err != nil {
+				continue
+			}
+			currentTheme.Set(t)
+			notifyConfigChange()
+		}
+	}()
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TestBuiltinThemes verifies every preset parses
+func TestBuiltinThemes(t *testing.T) {
+	names := themeNames()
+	for _, want := range []string{"default", "catppuccin", "gruvbox", "nord", "mono"} {
+		found := false
+		for _, name := range names {
+			found = found || name == want
+		}
+		if !found {
+			t.Errorf("missing built-in theme %q (have %v)", want, names)
+		}
+	}
+
+	for _, name := range names {
+		_, err := loadTheme(name)
+		assertNoError(t, err)
+	}
+}
+
+// TestParseTheme verifies theme files are validated
+func TestParseTheme(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"valid", "accent: [title]\nstyles:\n  border: {fg: \"#ff0000\", border: double}\n  dim: {fg: \"8\", bold: true}\n", false},
+		{"empty", "", false},
+		{"unknown role", "styles:\n  headline: {fg: \"1\"}\n", true},
+		{"unknown accent role", "accent: [headline]\n", true},
+		{"invalid fg", "styles:\n  title: {fg: red}\n", true},
+		{"invalid bg", "styles:\n  title: {bg: \"#12\"}\n", true},
+		{"unknown border", "styles:\n  border: {border: wavy}\n", true},
+		{"border on text role", "styles:\n  title: {border: rounded}\n", true},
+		{"not yaml", "styles: [", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseTheme([]byte(tt.data))
+			if tt.wantErr {
+				assertError(t, err, tt.name)
+			} else {
+				assertNoError(t, err)
+			}
+		})
+	}
+}
+
+// TestLoadThemeFromFile verifies user themes load from the themes directory
+// and take precedence over presets
+func TestLoadThemeFromFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	dir := filepath.Join(home, "goplaying", "themes")
+	assertNoError(t, os.MkdirAll(dir, 0o755))
+	assertNoError(t, os.WriteFile(filepath.Join(dir, "nord.yaml"), []byte("styles:\n  title: {fg: \"#123456\"}\n"), 0o644))
+
+	th, err := loadTheme("nord")
+	assertNoError(t, err)
+	assertEqual(t, th.Styles["title"].Fg, "#123456", "user file should override the preset")
+
+	_, err = loadTheme("missing")
+	assertError(t, err, "unknown theme")
+	_, err = loadTheme("../config")
+	assertError(t, err, "path in theme name")
+}
+
+// TestThemeStyles verifies how roles combine the theme with the accent and
+// extracted palette
+func TestThemeStyles(t *testing.T) {
+	cfg := Config{}
+	cfg.UI.Background = backgroundDark
+	def, err := loadTheme("default")
+	assertNoError(t, err)
+	nord, err := loadTheme("nord")
+	assertNoError(t, err)
+	mono, err := loadTheme("mono")
+	assertNoError(t, err)
+
+	// Manual color: the default theme follows ui.color, presets keep theirs
+	m := model{color: "#ff0000"}
+	s := m.styles(def, cfg)
+	assertEqual(t, s.title.GetForeground(), lipgloss.TerminalColor(lipgloss.Color("#ff0000")), "default title")
+	assertEqual(t, s.muted.GetForeground(), lipgloss.TerminalColor(lipgloss.Color(darkUIColors.muted)), "default muted")
+	assertEqual(t, s.border.GetBorderTopForeground(), lipgloss.TerminalColor(lipgloss.Color("#ff0000")), "default border")
+	s = m.styles(nord, cfg)
+	assertEqual(t, s.title.GetForeground(), lipgloss.TerminalColor(lipgloss.Color("#88c0d0")), "nord title")
+
+	// Extracted palette: overrides only the theme's accent roles
+	m.palette = palette{accent: "#ff0000", secondary: "#00ff00", muted: "#777777", border: "#0000ff"}
+	s = m.styles(def, cfg)
+	assertEqual(t, s.fill.GetForeground(), lipgloss.TerminalColor(lipgloss.Color("#00ff00")), "default fill")
+	assertEqual(t, s.muted.GetForeground(), lipgloss.TerminalColor(lipgloss.Color("#777777")), "default muted")
+	assertEqual(t, s.border.GetBorderTopForeground(), lipgloss.TerminalColor(lipgloss.Color("#0000ff")), "default border")
+	s = m.styles(nord, cfg)
+	assertEqual(t, s.title.GetForeground(), lipgloss.TerminalColor(lipgloss.Color("#ff0000")), "nord title")
+	assertEqual(t, s.label.GetForeground(), lipgloss.TerminalColor(lipgloss.Color("#81a1c1")), "nord label")
+	s = m.styles(mono, cfg)
+	assertEqual(t, s.title.GetForeground(), lipgloss.TerminalColor(lipgloss.NoColor{}), "mono title")
+	if !s.title.GetBold() {
+		t.Error("mono title should be bold")
+	}
+}
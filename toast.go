@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// How long a toast stays up. Errors get longer to be read.
+const (
+	toastDuration      = 3 * time.Second
+	toastErrorDuration = 6 * time.Second
+)
+
+// toast is a short notice shown where the help line is, so the card
+// underneath never moves: a command's result, a toggle, a failed control
+// or a rejected config reload. A newer one replaces it.
+type toast struct {
+	text   string
+	failed bool
+	until  time.Time // When it goes (zero = no toast)
+}
+
+// notify shows text as a toast
+func (m *model) notify(text string) {
+	m.toast = toast{text: text, until: time.Now().Add(toastDuration)}
+}
+
+// warn shows text as an error toast
+func (m *model) warn(text string) {
+	m.toast = toast{text: text, failed: true, until: time.Now().Add(toastErrorDuration)}
+}
+
+// notifyError shows err as a toast
+func (m *model) notifyError(err error) {
+	m.warn(err.Error())
+}
+
+// expireToast drops the toast once its time is up (on the UI tick)
+func (m *model) expireToast(now time.Time) {
+	if !m.toast.until.IsZero() && !now.Before(m.toast.until) {
+		m.toast = toast{}
+	}
+}
+
+// toastLine is the toast, cut to width ("" for none)
+func (m model) toastLine(styles themeStyles, width int) string {
+	switch {
+	case m.toast.text == "":
+		return ""
+	case m.toast.failed:
+		return styles.error.Render(truncateText(m.toast.text, width))
+	}
+	return styles.muted.Render(truncateText(m.toast.text, width))
+}
+
+// configErrorFields lists the settings validateConfig errors are about,
+// once each, in order
+func configErrorFields(errs []error) []string {
+	var fields []string
+	for _, err := range errs {
+		var ce configError
+		if errors.As(err, &ce) && !slices.Contains(fields, ce.field) {
+			fields = append(fields, ce.field)
+		}
+	}
+	return fields
+}
+
+// onOff is a toggle's toast ("Artwork on")
+func onOff(name string, on bool) string {
+	if on {
+		return name + " on"
+	}
+	return name + " off"
+}
+
+// animationToast is the toast for switching to an animation ("Vinyl on")
+func animationToast(name string) string {
+	if name == animationNone {
+		return "Animation off"
+	}
+	return onOff(strings.ToUpper(name[:1])+name[1:], true)
+}
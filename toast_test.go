@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestControlErrorToast verifies a failed control shows a toast and leaves
+// the card alone, until the toast times out
+func TestControlErrorToast(t *testing.T) {
+	defer config.Set(config.Get())
+	m, _ := mouseTestModel(t, layoutCard)
+
+	updated, _ := m.Update(controlMsg{err: errors.New("playerctl next failed")})
+	m = updated.(model)
+	assertEqual(t, m.lastError, nil, "card error")
+	view := m.View()
+	for _, want := range []string{"playerctl next failed", "Title", "Artist"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q", want)
+		}
+	}
+
+	m.expireToast(time.Now().Add(toastDuration))
+	assertEqual(t, m.toast.text, "playerctl next failed", "error toast outlasts a notice")
+	m.expireToast(time.Now().Add(toastErrorDuration))
+	assertEqual(t, m.toast.text, "", "toast timed out")
+	if strings.Contains(m.View(), "failed") {
+		t.Error("expired toast still shown")
+	}
+}
+
+// TestConfigRejectedToast verifies a rejected reload lists the settings it
+// was rejected for, once each
+func TestConfigRejectedToast(t *testing.T) {
+	rejected := []error{
+		configError{field: "ui.layout", message: "must be one of ..."},
+		configError{field: "artwork.padding", message: "must be >= 0"},
+		configError{field: "artwork.padding", message: "must be < ui.max_width"},
+	}
+	assertEqual(t, strings.Join(configErrorFields(rejected), ", "), "ui.layout, artwork.padding", "fields")
+
+	updated, cmd := model{}.Update(configReloadMsg{rejected: rejected})
+	got := updated.(model).toast
+	assertEqual(t, got.text, "Config not reloaded: ui.layout, artwork.padding", "toast")
+	assertEqual(t, got.failed, true, "shown as an error")
+	if cmd == nil {
+		t.Error("stopped watching the config")
+	}
+}
+
+// TestToggleToasts verifies toggles say what they switched to
+func TestToggleToasts(t *testing.T) {
+	defer config.Set(config.Get())
+	cfg := layoutTestConfig(layoutCard)
+	cfg.Artwork.Animation = animationNone
+	cfg.UI.TimeDisplay = timeTotal
+	config.Set(cfg)
+
+	press := func(m model, key string) model {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return updated.(model)
+	}
+	m := press(model{}, "a")
+	assertEqual(t, m.toast.text, "Artwork off", "a")
+	m = press(m, "v")
+	assertEqual(t, m.toast.text, "Vinyl on", "v")
+	m = press(m, "t")
+	assertEqual(t, m.toast.text, "Time: elapsed", "t")
+	assertEqual(t, animationToast(animationNone), "Animation off", "none")
+}
+
+// TestSourceToast verifies a switch to another player is announced, but
+// not the first one seen
+func TestSourceToast(t *testing.T) {
+	defer config.Set(config.Get())
+	config.Set(layoutTestConfig(layoutCard))
+
+	updated, _ := model{}.Update(songDataMsg{title: "Title", source: "spotify"})
+	assertEqual(t, updated.(model).toast.text, "", "first source")
+	updated, _ = updated.Update(songDataMsg{title: "Title", source: "spotify"})
+	assertEqual(t, updated.(model).toast.text, "", "same source")
+	updated, _ = updated.Update(songDataMsg{title: "Title", source: "firefox"})
+	assertEqual(t, updated.(model).toast.text, "Source: firefox", "switched")
+}
+
+// TestMiniToast verifies mini shows a toast in place of the text, keeping
+// the progress bar
+func TestMiniToast(t *testing.T) {
+	defer config.Set(config.Get())
+	m, _ := mouseTestModel(t, layoutMini)
+	m.height = 1
+	m.notify("Vinyl on")
+
+	view := m.View()
+	if !strings.Contains(view, "Vinyl on") || strings.Contains(view, "Title") {
+		t.Errorf("toast not in place of the text: %q", view)
+	}
+	if !strings.Contains(view, "█") {
+		t.Error("progress bar gone")
+	}
+}
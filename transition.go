@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nfnt/resize"
+)
+
+const (
+	transitionNone = "none"
+
+	// transitionFrames is how many in-between frames a cover transition is
+	// made of (the old and new covers themselves aren't counted)
+	transitionFrames = 8
+)
+
+// In-between frames for a cover transition, rendered in the background
+type transitionMsg struct {
+	job    int      // Transition job these frames belong to (stale jobs are ignored)
+	frames []string // Kitty transmit commands, old cover to new (nil = skip)
+}
+
+// Time to show the next transition frame
+type transitionStepMsg struct {
+	job int
+}
+
+// stillArtwork decodes raw artwork and returns it as displayed when static:
+// resized, filtered and, with an animation active, its first frame.
+// artworkHash 0 skips the base artwork cache.
+func stillArtwork(raw []byte, artworkHash uint64, cfg Config) (*image.RGBA, error) {
+	img, err := decodeArtworkData(raw)
+	if err != nil {
+		return nil, err
+	}
+	prepared := prepareArtwork(img, artworkHash, cfg)
+	if anim := activeAnimation(cfg); anim != nil {
+		return toRGBA(anim.frame(prepared.(*image.RGBA), 0, 1)), nil
+	}
+	return toRGBA(prepared), nil
+}
+
+// transitionFrame blends from (the old cover) into to at t (0-1). Covers of
+// different shapes are stretched onto the new cover's size.
+func transitionFrame(from, to *image.RGBA, t float64, mode string) *image.RGBA {
+	if from.Rect.Size() != to.Rect.Size() {
+		from = toRGBA(resize.Resize(uint(to.Rect.Dx()), uint(to.Rect.Dy()), from, resize.Bilinear))
+	}
+	out := image.NewRGBA(to.Rect)
+	w := to.Rect.Dx()
+
+	// Ease in and out, so the motion starts and settles gently
+	t = t * t * (3 - 2*t)
+
+	switch mode {
+	case "slide":
+		// The new cover pushes the old one out to the left
+		shift := int(math.Round(t * float64(w)))
+		for y := 0; y < to.Rect.Dy(); y++ {
+			for x := 0; x < w; x++ {
+				src, sx := from, x+shift
+				if sx >= w {
+					src, sx = to, sx-w
+				}
+				copy(out.Pix[out.PixOffset(x, y):out.PixOffset(x, y)+4], src.Pix[src.PixOffset(sx, y):])
+			}
+		}
+	default: // crossfade
+		for i := range out.Pix {
+			out.Pix[i] = uint8(float64(from.Pix[i])*(1-t) + float64(to.Pix[i])*t + 0.5)
+		}
+	}
+	return out
+}
+
+// renderTransitionCmd renders the in-between frames from the old cover to
+// the new one off the UI goroutine. Failures (e.g. an undecodable old cover)
+// deliver no frames, and the new cover just appears.
+func renderTransitionCmd(job int, oldRaw, newRaw []byte, cfg Config) tea.Cmd {
+	return func() (msg tea.Msg) {
+		msg = transitionMsg{job: job}
+		// Malformed images can panic inside image decoders; never crash the UI
+		defer func() {
+			if recover() != nil {
+				msg = transitionMsg{job: job}
+			}
+		}()
+
+		from, err := stillArtwork(oldRaw, 0, cfg)
+		if err != nil {
+			return msg
+		}
+		to, err := stillArtwork(newRaw, hashBytes(newRaw), cfg)
+		if err != nil {
+			return msg
+		}
+
+		frames := make([]string, 0, transitionFrames)
+		for i := 1; i <= transitionFrames; i++ {
+			t := float64(i) / float64(transitionFrames+1)
+			data, err := encodeFramePNG(nil, transitionFrame(from, to, t, cfg.Artwork.Transition), 0, 1)
+			if err != nil {
+				return msg
+			}
+			encoded, err := kittyTransmit(data, kittyImageID, cfg.Artwork.Transmission, kittyOwnerTransition)
+			if err != nil {
+				return msg
+			}
+			frames = append(frames, encoded)
+		}
+		return transitionMsg{job: job, frames: frames}
+	}
+}
+
+// transitionStepCmd schedules the next transition frame
+func transitionStepCmd(job int, cfg Config) tea.Cmd {
+	step := time.Duration(cfg.Artwork.TransitionMs) * time.Millisecond / (transitionFrames + 1)
+	return tea.Tick(step, func(time.Time) tea.Msg {
+		return transitionStepMsg{job: job}
+	})
+}
+
+// startTransition begins a transition from the artwork on screen to final,
+// the new cover's transmit command, which is shown once the in-between frames
+// have played. The old cover stays up while they render.
+func (m *model) startTransition(cfg Config, oldRaw []byte, final string) tea.Cmd {
+	m.transitionJob++
+	m.transitioning = true
+	m.transitionFrames = nil
+	m.transitionIndex = 0
+	m.transitionFinal = final
+	return renderTransitionCmd(m.transitionJob, oldRaw, m.rawArtworkData, cfg)
+}
+
+// presentArtwork shows encoded artwork, or holds it back until a running
+// transition ends
+func (m *model) presentArtwork(encoded string) {
+	if m.transitioning {
+		m.transitionFinal = encoded
+		return
+	}
+	m.showArtwork(encoded)
+}
+
+// stepTransition shows the next transition frame, ending the transition
+// with the new cover after the last one. Returns whether frames remain.
+func (m *model) stepTransition() bool {
+	if m.transitionIndex < len(m.transitionFrames) {
+		m.showArtwork(m.transitionFrames[m.transitionIndex])
+		m.transitionIndex++
+		return true
+	}
+	m.endTransition()
+	return false
+}
+
+// endTransition shows whatever the transition was holding back
+func (m *model) endTransition() {
+	final := m.transitionFinal
+	m.transitioning = false
+	m.transitionFrames = nil
+	m.transitionIndex = 0
+	m.transitionFinal = ""
+	kittyFiles.release(kittyOwnerTransition)
+	if final != "" {
+		m.showArtwork(final)
+	}
+}
+
+// fadeColorTo starts fading the accent color toward color over
+// artwork.transition_ms. Colors that aren't hex (e.g. ANSI codes) snap, as
+// does everything when transitions are off.
+func (m *model) fadeColorTo(color string, cfg Config) {
+	from, okFrom := parseHexColor(m.color)
+	to, okTo := parseHexColor(color)
+	if cfg.Artwork.Transition == transitionNone || !okFrom || !okTo || from == to {
+		m.color = color
+		m.colorFadeTo = ""
+		return
+	}
+	m.colorFadeFrom = from
+	m.colorFadeTo = color
+	m.colorFadeStart = time.Now()
+}
+
+// updateColorFade moves the accent color along a running fade
+func (m *model) updateColorFade(cfg Config) {
+	if m.colorFadeTo == "" {
+		return
+	}
+	to, _ := parseHexColor(m.colorFadeTo)
+	duration := time.Duration(cfg.Artwork.TransitionMs) * time.Millisecond
+	t := 1.0
+	if duration > 0 {
+		t = clamp01(float64(time.Since(m.colorFadeStart)) / float64(duration))
+	}
+	if t >= 1 {
+		m.color = m.colorFadeTo
+		m.colorFadeTo = ""
+		return
+	}
+	var rgb [3]uint8
+	for c := 0; c < 3; c++ {
+		rgb[c] = uint8(float64(m.colorFadeFrom[c])*(1-t) + float64(to[c])*t + 0.5)
+	}
+	m.color = fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2])
+}
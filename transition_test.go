@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+)
+
+// TestTransitionFrame verifies crossfade blends and slide pushes the old
+// cover out, including covers of different sizes
+func TestTransitionFrame(t *testing.T) {
+	black := generateTestImage(8, 8, color.RGBA{0, 0, 0, 255})
+	white := generateTestImage(8, 8, color.RGBA{255, 255, 255, 255})
+
+	mid := transitionFrame(black, white, 0.5, "crossfade")
+	if got := mid.RGBAAt(4, 4); got.R < 120 || got.R > 135 {
+		t.Errorf("crossfade midpoint = %v, want mid gray", got)
+	}
+
+	slide := transitionFrame(black, white, 0.5, "slide")
+	if got := slide.RGBAAt(0, 0); got.R != 0 {
+		t.Errorf("slide left edge = %v, want the old cover", got)
+	}
+	if got := slide.RGBAAt(7, 0); got.R != 255 {
+		t.Errorf("slide right edge = %v, want the new cover", got)
+	}
+
+	small := generateTestImage(4, 2, color.RGBA{0, 0, 0, 255})
+	if got := transitionFrame(small, white, 0.5, "crossfade").Rect; got != white.Rect {
+		t.Errorf("mismatched sizes: frame is %v, want %v", got, white.Rect)
+	}
+}
+
+// TestRenderTransition verifies in-between frames render off the UI
+// goroutine, and undecodable covers skip the transition
+func TestRenderTransition(t *testing.T) {
+	encode := func(c color.RGBA) []byte {
+		var buf bytes.Buffer
+		assertNoError(t, png.Encode(&buf, generateTestImage(16, 16, c)))
+		return buf.Bytes()
+	}
+	cfg := Config{}
+	cfg.Artwork.WidthPixels = 16
+	cfg.Artwork.Transition = "crossfade"
+	cfg.Artwork.Transmission = kittyTransmitDirect
+
+	msg := renderTransitionCmd(3, encode(color.RGBA{255, 0, 0, 255}), encode(color.RGBA{0, 0, 255, 255}), cfg)().(transitionMsg)
+	if msg.job != 3 || len(msg.frames) != transitionFrames {
+		t.Errorf("got job %d with %d frames, want job 3 with %d", msg.job, len(msg.frames), transitionFrames)
+	}
+
+	msg = renderTransitionCmd(4, []byte("not an image"), encode(color.RGBA{0, 0, 255, 255}), cfg)().(transitionMsg)
+	if msg.frames != nil {
+		t.Error("undecodable old cover should skip the transition")
+	}
+}
+
+// TestTransitionHoldsArtwork verifies artwork arriving mid-transition is shown
+// only once the in-between frames have played
+func TestTransitionHoldsArtwork(t *testing.T) {
+	m := model{artworkEncoded: "old"}
+	m.startTransition(Config{}, []byte("old"), "new")
+
+	m.presentArtwork("frame0")
+	if m.artworkEncoded != "old" {
+		t.Fatalf("artwork changed mid-transition: %q", m.artworkEncoded)
+	}
+
+	m.transitionFrames = []string{"a", "b"}
+	if !m.stepTransition() || m.artworkEncoded != "a" {
+		t.Errorf("first step showed %q, want a", m.artworkEncoded)
+	}
+	m.stepTransition()
+	if m.stepTransition() {
+		t.Error("transition should end after its frames")
+	}
+	if m.artworkEncoded != "frame0" || m.transitioning {
+		t.Errorf("after transition: showing %q, transitioning %v", m.artworkEncoded, m.transitioning)
+	}
+
+	m.startTransition(Config{}, []byte("old"), "next")
+	m.resetArtworkState()
+	if m.transitioning || m.transitionFinal != "" {
+		t.Error("reset should drop the transition")
+	}
+}
+
+// TestColorFade verifies the accent color interpolates toward the new color
+// and snaps when it can't
+func TestColorFade(t *testing.T) {
+	cfg := Config{}
+	cfg.Artwork.Transition = "crossfade"
+	cfg.Artwork.TransitionMs = 400
+
+	m := model{color: "#000000"}
+	m.fadeColorTo("#ffffff", cfg)
+	m.colorFadeStart = time.Now().Add(-200 * time.Millisecond)
+	m.updateColorFade(cfg)
+	if rgb, ok := parseHexColor(m.color); !ok || rgb[0] < 100 || rgb[0] > 160 {
+		t.Errorf("half-way color = %q, want mid gray", m.color)
+	}
+
+	m.colorFadeStart = time.Now().Add(-time.Second)
+	m.updateColorFade(cfg)
+	if m.color != "#ffffff" || m.colorFadeTo != "" {
+		t.Errorf("finished fade: color %q, fading to %q", m.color, m.colorFadeTo)
+	}
+
+	m = model{color: "2"}
+	m.fadeColorTo("#ffffff", cfg)
+	if m.color != "#ffffff" {
+		t.Errorf("ANSI color should snap, got %q", m.color)
+	}
+
+	cfg.Artwork.Transition = transitionNone
+	m = model{color: "#000000"}
+	m.fadeColorTo("#ffffff", cfg)
+	if m.color != "#ffffff" {
+		t.Errorf("transitions off should snap, got %q", m.color)
+	}
+}
@@ -10,128 +10,164 @@ import (
 
 func (m model) View() string {
 	// Get config snapshot for rendering
-	cfg := config.Get()
+	ui, hits := m.render(config.Get())
+	if m.hits != nil {
+		*m.hits = hits
+	}
+	return ui
+}
+
+// render draws the UI, and says where its clickable parts ended up
+func (m model) render(cfg Config) (string, hitMap) {
+	if m.quitting {
+		return "", hitMap{}
+	}
 
 	// Calculate current interpolated position for smooth progress bar
 	currentPos := m.getCurrentPosition()
-	currentTime := formatTime(int64(currentPos))
 	var progress float64
 	if m.duration > 0 {
 		progress = currentPos / float64(m.duration)
 	}
 
-	// Use lipgloss.Color to validate the color input
-	color := lipgloss.Color(m.color)
-	highlight := lipgloss.NewStyle().Foreground(color)
-	white := lipgloss.NewStyle().Foreground(lipgloss.Color("15")) // ANSI white
-
-	borderStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(color).
-		Padding(1, 2)
-
-	labelStyle := lipgloss.NewStyle().Foreground(color).Bold(true)
-	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
-	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
-	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
+	// Theme styles, with the accent (or extracted palette) filled in
+	styles := m.styles(currentTheme.Get(), cfg)
+	lay := m.layout(cfg)
+	icons := iconsFor(cfg)
+	if lay.name == layoutMini {
+		return m.renderMini(cfg, lay, styles)
+	}
+	heading := ""
+	if lay.heading {
+		heading = styles.title.Render(withIcon(icons.nowPlaying, "Now Playing")) + "\n\n"
+	}
 
 	var textContent strings.Builder
 	var progressBarContent string
+	var barWidth int
 
 	if m.lastError != nil {
 		// Idle state (nothing playing) vs actual error
 		if errors.Is(m.lastError, ErrNothingPlaying) {
 			// Show friendly placeholder for "nothing playing" state
-			textContent.WriteString(highlight.Render("󰓃 Now Playing") + "\n\n")
-			textContent.WriteString(mutedStyle.Render("Nothing playing") + "\n\n")
-			textContent.WriteString(dimStyle.Render("Start playing music to begin"))
+			textContent.WriteString(heading)
+			textContent.WriteString(styles.muted.Render("Nothing playing") + "\n\n")
+			textContent.WriteString(styles.dim.Render("Start playing music to begin"))
 		} else {
 			// Actual error - show in muted color (not bright red)
-			textContent.WriteString(errorStyle.Render("Error: " + m.lastError.Error()))
+			textContent.WriteString(styles.error.Render("Error: " + m.lastError.Error()))
 		}
 	} else {
-		textContent.WriteString(highlight.Render("󰓃 Now Playing") + "\n\n")
+		textContent.WriteString(heading)
 
 		addLine := func(label, value string) {
-			if value != "" {
-				textContent.WriteString(
-					fmt.Sprintf("%s %s\n",
-						labelStyle.Render(label),
-						value,
-					),
-				)
+			if value == "" {
+				return
 			}
+			if icons.labelWidth() == 0 {
+				// No icons (ui.icons: none): no label column either
+				textContent.WriteString(value + "\n")
+				return
+			}
+			// Wrapped rows continue under the text, past the label column
+			value = strings.ReplaceAll(value, "\n", "\n"+strings.Repeat(" ", icons.labelWidth()))
+			textContent.WriteString(
+				fmt.Sprintf("%s %s\n",
+					styles.label.Render(icons.label(label)+" "),
+					value,
+				),
+			)
 		}
 
-		// Calculate max length for text
-		maxLen := cfg.Text.MaxLengthWithArt
-		if !m.supportsKitty || !cfg.Artwork.Enabled {
-			maxLen = cfg.Text.MaxLengthNoArt
-		}
+		// Text scrolls past the width the layout leaves it
+		maxLen := lay.textWidth
 
-		addLine("󰎈 ", scrollText(m.songData.Title, maxLen, m.scrollOffset))
-		addLine("󰠃 ", scrollText(m.songData.Artist, maxLen, m.scrollOffset))
-		addLine("󰀥 ", scrollText(m.songData.Album, maxLen, m.scrollOffset))
-
-		// Use different icon based on play state (case-insensitive)
-		statusIcon := "󰐊 " // play icon (default)
-		statusLower := strings.ToLower(m.songData.Status)
-		if statusLower == "paused" {
-			statusIcon = "󰏤 " // pause icon
-		} else if statusLower == "stopped" {
-			statusIcon = "󰓛 " // stop icon
+		// Template-driven lines (ui.lines), each scrolling on its own
+		overflow := m.overflow(cfg, lay)
+		for i, line := range m.renderLines(cfg) {
+			addLine(line.label, fitText(line.text, maxLen, overflow, m.scrollOffset(i)))
 		}
-		addLine(statusIcon, m.songData.Status)
 
 		if progress > 0 {
 			// Progress bar with smooth interpolated position - will be placed below
-			// Bar width calculated from max_width, leaving room for timestamps
-			barWidth := cfg.UI.MaxWidth - 17
-			if barWidth < 0 {
-				barWidth = 0
-			}
-			if progress > 1 {
-				progress = 1
+			// Bar width comes from the layout, leaving room for timestamps
+			timeText := m.timeText(cfg)
+			barWidth = progressBarWidth(lay.barWidth, timeText)
+			progressBar := renderBar(cfg, styles, barWidth, progress)
+			if m.hoverBar {
+				// The time under the pointer, in the same room
+				timeText = lipgloss.NewStyle().Width(cellWidth(timeText)).Render(formatTime(int64(m.hoverPosition)))
 			}
-			filled := int(float64(barWidth) * progress)
-			if filled > barWidth {
-				filled = barWidth
-			}
-			progressBar := highlight.Render(strings.Repeat("█", filled)) +
-				white.Render(strings.Repeat("─", barWidth-filled))
 
 			progressBarContent = fmt.Sprintf(
-				"\n%s %s/%s",
+				"\n%s %s",
 				progressBar,
-				highlight.Render(currentTime),
-				highlight.Render(m.songData.TotalTime),
+				styles.title.Render(timeText),
 			)
 		}
 	}
 
 	// Combine artwork and text content
 	var topSection string
-	if m.artworkEncoded != "" && m.supportsKitty && cfg.Artwork.Enabled {
-		// If we need to force delete (e.g., after resize), send delete ALL command first
-		// Use d=A to delete all images, not just ID 42, to clear any stale placements
+	if m.artworkEncoded != "" && lay.artColumns > 0 {
+		// If we need to force delete (e.g., after resize), drop all placements
+		// first to clear any stale ones. Image data is kept, so the placement
+		// below redraws without re-uploading.
 		var deleteCmd string
 		if m.forceDeleteImg {
-			deleteCmd = "\033_Ga=d,d=A\033\\"
+			deleteCmd = kittyDeletePlacements
 		}
 
-		// Add padding to the left of text to make room for the image
-		paddedText := lipgloss.NewStyle().
-			PaddingLeft(cfg.Artwork.Padding).
-			Render(textContent.String())
+		// Upload the image data only while a new image is pending; otherwise
+		// re-place the already-uploaded image with a tiny command
+		var uploadCmd string
+		if m.artworkUploadPending {
+			uploadCmd = m.artworkEncoded
+		}
 
-		// Always send Kitty protocol data — Bubble Tea redraws the full screen
-		// on every View(), so the image must be re-sent each render to persist
-		topSection = deleteCmd + m.artworkEncoded + paddedText
+		// Make room for the image: beside the text (card), or above it with
+		// the image centered and the text centered below
+		var prefix, paddedText string
+		if lay.artRows > 0 {
+			prefix = strings.Repeat(" ", (lay.innerWidth()-lay.artColumns)/2)
+			text := textContent.String()
+			paddedText = strings.Repeat("\n", lay.artRows) + lipgloss.NewStyle().
+				PaddingLeft(max(lay.innerWidth()-lipgloss.Width(text), 0)/2).
+				Render(text)
+		} else {
+			paddedText = lipgloss.NewStyle().
+				PaddingLeft(lay.textIndent).
+				Render(textContent.String())
+		}
+
+		// Terminal-side animation: play while playing, stop on pause
+		var animationCmd string
+		if m.animNative {
+			animationCmd = kittyAnimationControl(kittyImageID, m.isPlaying)
+		}
+
+		// Tonearm overlay, stacked above the record
+		var tonearmCmd string
+		if m.showTonearm(cfg) && m.tonearmEncoded != "" {
+			if m.tonearmUploadPending {
+				tonearmCmd = m.tonearmEncoded
+			}
+			tonearmCmd += kittyPlaceAbove(kittyTonearmID, lay.artColumns, 1)
+		} else if m.tonearmEncoded != "" {
+			// Hidden since the last tick - remove it until updateTonearm forgets it
+			tonearmCmd = kittyDeleteImage(kittyTonearmID)
+		}
+
+		topSection = prefix + deleteCmd + uploadCmd + kittyPlace(kittyImageID, lay.artColumns) + animationCmd + tonearmCmd + paddedText
+	} else if m.artworkEncoded != "" && m.supportsKitty && cfg.Artwork.Enabled {
+		// The layout has no room for artwork: hide it, but keep the image
+		// data so it can come back without re-uploading
+		topSection = kittyDeletePlacements + textContent.String()
 	} else {
 		// No artwork - delete any existing image and show content without padding
 		if m.supportsKitty {
 			// Send delete command for all images
-			topSection = "\033_Ga=d,d=A\033\\" + textContent.String()
+			topSection = kittyDeleteAll + textContent.String()
 		} else {
 			topSection = textContent.String()
 		}
@@ -145,37 +181,58 @@ func (m model) View() string {
 		mainContent = topSection
 	}
 
-	contentStr := borderStyle.
-		Width(cfg.UI.MaxWidth).
+	contentStr := styles.border.
+		Padding(lay.padY, lay.padX).
+		Width(lay.boxWidth).
 		Render(mainContent)
 
-	// Build help text - either full help or hint to press ?
+	// Build help text from the keymap - either full help or the hint to
+	// press the help key. The command prompt, then a toast, take its place.
 	var helpText string
-	if m.showHelp {
+	keys := m.keyBindings(cfg)
+	if prompt := m.promptLine(styles, lay.boxWidth); prompt != "" {
+		helpText = prompt
+	} else if toast := m.toastLine(styles, lay.boxWidth); toast != "" {
+		helpText = toast
+	} else if m.showHelp {
+		items := keys.help(styles.title)
+		for i := 1; i < len(items); i++ {
+			items[i] = "  " + items[i]
+		}
 		helpText = lipgloss.NewStyle().
-			Width(cfg.UI.MaxWidth).
+			Width(lay.boxWidth).
 			Align(lipgloss.Center).
-			Render(lipgloss.JoinHorizontal(
-				lipgloss.Center,
-				"Play/Pause: "+highlight.Render("p"),
-				"  Next: "+highlight.Render("n"),
-				"  Previous: "+highlight.Render("b"),
-				"  Toggle Art: "+highlight.Render("a"),
-				"  Toggle Vinyl: "+highlight.Render("v"),
-				"  Quit: "+highlight.Render("q"),
-				"  Hide: "+highlight.Render("?"),
-			))
-	} else {
-		helpText = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("240")).
-			Render("Press ? for help")
+			Render(lipgloss.JoinHorizontal(lipgloss.Center, items...))
+	} else if helpKeys := keys.keys[actionHelp]; len(helpKeys) > 0 {
+		helpText = styles.muted.Render("Press " + keyLabel(helpKeys[0]) + " for help")
 	}
 
-	fullUI := lipgloss.JoinVertical(lipgloss.Center, contentStr, "\n"+helpText)
+	// Transport buttons between the box and the help line
+	blocks := []string{contentStr}
+	var controls string
+	var buttons []button
+	if lay.controls {
+		controls, buttons = m.controls(styles, icons)
+		blocks = append(blocks, controls)
+	}
+	blocks = append(blocks, "\n"+helpText)
+	fullUI := lipgloss.JoinVertical(lipgloss.Center, blocks...)
+
+	// Where the box, its progress bar (the last row inside the border and
+	// padding) and the buttons ended up on screen
+	x, y := m.placeOffset(cfg, fullUI, lipgloss.Center)
+	boxWidth, boxHeight := lipgloss.Width(contentStr), lipgloss.Height(contentStr)
+	boxX := x + centerOffset(lipgloss.Width(fullUI), boxWidth)
+	hits := hitMap{card: region{boxX, y, boxWidth, boxHeight}}
+	if progressBarContent != "" {
+		hits.bar = region{boxX + 1 + lay.padX, y + boxHeight - 2 - lay.padY, barWidth, 1}
+	}
+	rowX := x + centerOffset(lipgloss.Width(fullUI), lipgloss.Width(controls))
+	for _, b := range buttons {
+		b.x += rowX
+		b.y = y + boxHeight
+		hits.buttons = append(hits.buttons, b)
+	}
 
-	return lipgloss.Place(
-		m.width, m.height,
-		lipgloss.Center, lipgloss.Center,
-		fullUI,
-	)
+	return m.place(cfg, fullUI, lipgloss.Center), hits
 }
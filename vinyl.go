@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"math"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// vinylAnimation spins the artwork like a record (artwork.animation: vinyl)
+type vinylAnimation struct{}
+
+// prepare crops the artwork to a record. Record details are rotationally
+// symmetric, so they're drawn once here and simply rotate with the frame.
+func (vinylAnimation) prepare(img image.Image, cfg Config) *image.RGBA {
+	record := cropToCircle(img).(*image.RGBA)
+	drawRecordDetails(record, cfg)
+	return record
+}
+
+// frame rotates the record index/total of a revolution
+func (vinylAnimation) frame(prepared *image.RGBA, index int, total int) image.Image {
+	if index == 0 {
+		return prepared
+	}
+	return rotateImage(prepared, float64(index)*360/float64(total))
+}
+
+func (vinylAnimation) maxFrames(cfg Config) int {
+	return cfg.Artwork.VinylFrames
+}
+
+// cycle is one revolution at vinyl_rpm
+func (vinylAnimation) cycle(cfg Config) time.Duration {
+	return rpmCycle(cfg.Artwork.VinylRPM)
+}
+
+// rpmCycle returns how long one revolution takes at rpm (0 when stopped)
+func rpmCycle(rpm float64) time.Duration {
+	if rpm <= 0 {
+		return 0
+	}
+	return time.Duration(float64(time.Minute) / rpm)
+}
+
+const (
+	// Record detail proportions, relative to the record's radius
+	vinylLabelRadius   = 0.34  // Center label (the artwork stays visible inside it)
+	vinylLabelRingW    = 0.018 // Thickness of the ring around the label
+	vinylSpindleRadius = 0.035 // Hole in the middle
+	vinylGrooveSpacing = 0.025 // Distance between groove highlights
+	vinylGrooveDepth   = 0.22  // How much grooves darken the artwork (0-1)
+
+	// Tonearm travel: angle (degrees, clockwise from straight down) at the
+	// start and end of the track
+	tonearmStartAngle = 18.0
+	tonearmEndAngle   = 38.0
+)
+
+// drawRecordDetails adds the optional skeuomorphic record details to a
+// circle-cropped record in place: groove shading outside the label, a ring
+// around the label and a spindle hole (artwork.vinyl_grooves/_label/_spindle)
+func drawRecordDetails(record *image.RGBA, cfg Config) {
+	a := cfg.Artwork
+	if !a.VinylGrooves && !a.VinylLabel && !a.VinylSpindle {
+		return
+	}
+
+	size := record.Rect.Dx()
+	radius := float64(size) / 2
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dx := float64(x) + 0.5 - radius
+			dy := float64(y) + 0.5 - radius
+			// Distance from center as a fraction of the radius
+			r := math.Sqrt(dx*dx+dy*dy) / radius
+			if r > 1 {
+				continue
+			}
+
+			i := record.PixOffset(x, y)
+			px := record.Pix[i : i+4 : i+4]
+
+			// Grooves: concentric rings of slight darkening, fading in from
+			// the label edge so the label itself stays clean
+			if a.VinylGrooves && r > vinylLabelRadius {
+				wave := 0.5 + 0.5*math.Sin(2*math.Pi*r/vinylGrooveSpacing)
+				scalePixel(px, 1-vinylGrooveDepth*wave)
+			}
+
+			// Label ring: a dark anti-aliased band around the label
+			if a.VinylLabel {
+				edge := math.Abs(r-vinylLabelRadius) * radius
+				if coverage := clamp01(vinylLabelRingW*radius/2 - edge + 0.5); coverage > 0 {
+					scalePixel(px, 1-0.75*coverage)
+				}
+			}
+
+			// Spindle hole: transparent, anti-aliased against the label
+			if a.VinylSpindle {
+				if coverage := clamp01(vinylSpindleRadius*radius - r*radius + 0.5); coverage > 0 {
+					for c := 0; c < 4; c++ {
+						px[c] = uint8(float64(px[c]) * (1 - coverage))
+					}
+				}
+			}
+		}
+	}
+}
+
+// scalePixel darkens a premultiplied RGBA pixel by factor, keeping its alpha
+func scalePixel(px []uint8, factor float64) {
+	for c := 0; c < 3; c++ {
+		px[c] = uint8(float64(px[c])*factor + 0.5)
+	}
+}
+
+// clamp01 limits v to [0, 1]
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// tonearmAngle maps track progress (0-1) to the tonearm's angle in whole
+// degrees, so the overlay only re-renders when it visibly moves
+func tonearmAngle(progress float64) int {
+	progress = clamp01(progress)
+	return int(math.Round(tonearmStartAngle + progress*(tonearmEndAngle-tonearmStartAngle)))
+}
+
+// renderTonearm draws a tonearm overlay the same size as the record: a pivot
+// in the top-right corner and an arm swung angleDegrees toward the center,
+// everything else transparent
+func renderTonearm(size int, angleDegrees int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	s := float64(size)
+
+	pivotX, pivotY := s*0.94, s*0.06
+	length := s * 0.78
+	rad := float64(angleDegrees) * math.Pi / 180
+	// Arm hangs down from the pivot, swinging left (toward the record) as
+	// the angle grows
+	endX := pivotX - length*math.Sin(rad)
+	endY := pivotY + length*math.Cos(rad)
+
+	armWidth := s * 0.012
+	headLen := s * 0.07
+	headWidth := s * 0.03
+	pivotRadius := s * 0.045
+
+	arm := [3]uint8{200, 200, 205}
+	head := [3]uint8{60, 60, 65}
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			px, py := float64(x)+0.5, float64(y)+0.5
+
+			// Headshell: a thicker segment at the arm's end
+			hx := endX - headLen*math.Sin(rad)
+			hy := endY + headLen*math.Cos(rad)
+			if c := clamp01(headWidth/2 - distanceToSegment(px, py, endX, endY, hx, hy) + 0.5); c > 0 {
+				blendPixel(img, x, y, head, c)
+				continue
+			}
+
+			// Arm
+			if c := clamp01(armWidth/2 - distanceToSegment(px, py, pivotX, pivotY, endX, endY) + 0.5); c > 0 {
+				blendPixel(img, x, y, arm, c)
+			}
+
+			// Pivot base
+			dx, dy := px-pivotX, py-pivotY
+			if c := clamp01(pivotRadius - math.Sqrt(dx*dx+dy*dy) + 0.5); c > 0 {
+				blendPixel(img, x, y, head, c)
+			}
+		}
+	}
+	return img
+}
+
+// distanceToSegment returns the distance from (px, py) to segment a-b
+func distanceToSegment(px, py, ax, ay, bx, by float64) float64 {
+	vx, vy := bx-ax, by-ay
+	t := clamp01(((px-ax)*vx + (py-ay)*vy) / (vx*vx + vy*vy))
+	dx := px - (ax + t*vx)
+	dy := py - (ay + t*vy)
+	return math.Sqrt(dx*dx + dy*dy)
+}
+
+// blendPixel composites an opaque color at the given coverage over a pixel
+func blendPixel(img *image.RGBA, x, y int, rgb [3]uint8, coverage float64) {
+	i := img.PixOffset(x, y)
+	px := img.Pix[i : i+4 : i+4]
+	for c := 0; c < 3; c++ {
+		px[c] = uint8(float64(rgb[c])*coverage + float64(px[c])*(1-coverage) + 0.5)
+	}
+	px[3] = uint8(255*coverage + float64(px[3])*(1-coverage) + 0.5)
+}
+
+// Result of rendering the tonearm overlay in background
+type tonearmMsg struct {
+	angle   int    // Angle the overlay was rendered at
+	encoded string // Kitty transmit command for kittyTonearmID
+}
+
+// renderTonearmCmd renders and encodes the tonearm overlay off the UI goroutine
+func renderTonearmCmd(angle int) tea.Cmd {
+	cfg := config.Get()
+	return func() tea.Msg {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, renderTonearm(cfg.Artwork.WidthPixels, angle)); err != nil {
+			return nil
+		}
+		encoded, err := kittyTransmit(buf.Bytes(), kittyTonearmID, cfg.Artwork.Transmission, kittyOwnerTonearm)
+		if err != nil {
+			return nil
+		}
+		return tonearmMsg{angle: angle, encoded: encoded}
+	}
+}